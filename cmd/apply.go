@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var manifestFile string
+
+func init() {
+	for _, c := range []*cobra.Command{
+		applyCmd,
+		diffCmd,
+	} {
+		c.Flags().BoolVarP(&quiet, "quiet", "q", false, "suppress output")
+		c.Flags().StringVarP(&manifestFile, "file", "f", "cm.yaml", "manifest file describing Selenoid and Selenoid UI (YAML or JSON)")
+	}
+	applyCmd.Flags().DurationVarP(&waitTimeout, "wait-timeout", "", 30*time.Second, "how much time to wait for service to become ready (0 to disable)")
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Bring Selenoid and Selenoid UI to the state described in manifest file",
+	Run: func(cmd *cobra.Command, args []string) {
+		planImpl(true)
+	},
+}
+
+func planImpl(apply bool) {
+	manifest, err := selenoid.LoadManifest(manifestFile)
+	if err != nil {
+		stderr("Failed to load manifest: %v\n", err)
+		os.Exit(1)
+	}
+	if manifest.Selenoid != nil {
+		planServiceImpl(manifest.Selenoid.LifecycleConfig(), func(lc *selenoid.Lifecycle) *selenoid.Plan {
+			return lc.PlanSelenoid(manifest.Selenoid)
+		}, apply)
+	}
+	if manifest.UI != nil {
+		planServiceImpl(manifest.UI.LifecycleConfig(), func(lc *selenoid.Lifecycle) *selenoid.Plan {
+			return lc.PlanUI(manifest.UI)
+		}, apply)
+	}
+	os.Exit(0)
+}
+
+func planServiceImpl(config *selenoid.LifecycleConfig, planAction func(*selenoid.Lifecycle) *selenoid.Plan, apply bool) {
+	config.Quiet = quiet
+	config.WaitTimeout = waitTimeout
+	lifecycle, err := selenoid.NewLifecycle(config)
+	if err != nil {
+		stderr("Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	defer lifecycle.Close()
+	plan := planAction(lifecycle)
+	if plan.Empty() {
+		lifecycle.Titlef("%s is up to date", plan.Service)
+		return
+	}
+	lifecycle.Titlef("%s changes:", plan.Service)
+	for _, change := range plan.Changes {
+		lifecycle.Pointf("%s: %s", change.Action, change.Reason)
+	}
+	if !apply {
+		return
+	}
+	err = lifecycle.Apply(plan)
+	if err != nil {
+		lifecycle.Errorf("Failed to apply manifest: %v\n", err)
+		os.Exit(1)
+	}
+	lifecycle.Titlef("Successfully applied %s changes", plan.Service)
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show changes needed to bring Selenoid and Selenoid UI to the state described in manifest file",
+	Run: func(cmd *cobra.Command, args []string) {
+		planImpl(false)
+	},
+}
@@ -6,9 +6,10 @@ import (
 )
 
 var (
-	quiet    bool
-	registry string
-	rootCmd  = &cobra.Command{
+	quiet           bool
+	registry        string
+	registryMirrors []string
+	rootCmd         = &cobra.Command{
 		Use:   "cm",
 		Short: "cm is a configuration management tool for Aerokube products",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -20,6 +21,8 @@ var (
 func init() {
 	rootCmd.AddCommand(selenoidCmd)
 	rootCmd.AddCommand(selenoidUICmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
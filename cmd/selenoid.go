@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/aerokube/cm/selenoid"
@@ -12,6 +13,8 @@ import (
 
 var (
 	lastVersions    int
+	tagFilter       string
+	prereleases     bool
 	tmpfs           int
 	shmSize         int
 	operatingSystem string
@@ -20,7 +23,11 @@ var (
 	browsers        string
 	useDrivers      bool
 	browsersJson    string
+	catalog         string
+	parallel        int
+	pinDigests      bool
 	driversInfoUrl  string
+	cacheDir        string
 	configDir       string
 	uiConfigDir     string
 	skipDownload    bool
@@ -35,6 +42,16 @@ var (
 	uiPort          uint16
 	userNS          string
 	disableLogs     bool
+	waitTimeout     time.Duration
+	instance        string
+	adopt           bool
+	detach          bool
+	retries         int
+	retryBackoff    time.Duration
+	retryJitter     float64
+	engine          string
+	dockerHost      string
+	dockerContext   string
 )
 
 func init() {
@@ -48,6 +65,25 @@ func init() {
 	selenoidCmd.AddCommand(selenoidUpdateCmd)
 	selenoidCmd.AddCommand(selenoidCleanupCmd)
 	selenoidCmd.AddCommand(selenoidStatusCmd)
+	selenoidCmd.AddCommand(selenoidListCmd)
+	selenoidCmd.AddCommand(selenoidSuperviseCmd)
+	selenoidCmd.AddCommand(selenoidLogsCmd)
+	selenoidCmd.AddCommand(selenoidSystemdCmd)
+	selenoidCmd.AddCommand(selenoidExportCmd)
+	selenoidCmd.AddCommand(selenoidBundleCmd)
+	selenoidCmd.AddCommand(selenoidVerifyCmd)
+	selenoidCmd.AddCommand(selenoidUpgradeCmd)
+	selenoidCmd.AddCommand(selenoidRollbackCmd)
+	selenoidCmd.AddCommand(selenoidPruneCmd)
+	selenoidCmd.AddCommand(selenoidBrowsersCmd)
+	selenoidCmd.AddCommand(selenoidReloadCmd)
+	selenoidCmd.AddCommand(selenoidWriteLogCmd)
+
+	selenoidBundleCmd.AddCommand(selenoidBundleCreateCmd)
+	selenoidBundleCmd.AddCommand(selenoidBundleInstallCmd)
+	selenoidBrowsersCmd.AddCommand(selenoidBrowsersListCmd)
+	selenoidBrowsersCmd.AddCommand(selenoidBrowsersAddCmd)
+	selenoidBrowsersCmd.AddCommand(selenoidBrowsersRemoveCmd)
 
 	selenoidUICmd.AddCommand(selenoidDownloadUICmd)
 	selenoidUICmd.AddCommand(selenoidUIArgsCmd)
@@ -56,6 +92,8 @@ func init() {
 	selenoidUICmd.AddCommand(selenoidUpdateUICmd)
 	selenoidUICmd.AddCommand(selenoidCleanupUICmd)
 	selenoidUICmd.AddCommand(selenoidUIStatusCmd)
+	selenoidUICmd.AddCommand(selenoidUILogsCmd)
+	selenoidUICmd.AddCommand(selenoidUISystemdCmd)
 }
 
 func initFlags() {
@@ -64,30 +102,69 @@ func initFlags() {
 		selenoidArgsCmd,
 		selenoidConfigureCmd,
 		selenoidStartCmd,
+		selenoidSystemdCmd,
 		selenoidStopCmd,
 		selenoidUpdateCmd,
 		selenoidCleanupCmd,
 		selenoidStatusCmd,
+		selenoidLogsCmd,
 		selenoidDownloadUICmd,
 		selenoidUIArgsCmd,
 		selenoidStartUICmd,
+		selenoidUISystemdCmd,
 		selenoidStopUICmd,
 		selenoidUpdateUICmd,
 		selenoidCleanupUICmd,
 		selenoidUIStatusCmd,
+		selenoidUILogsCmd,
+		selenoidBundleCreateCmd,
+		selenoidBundleInstallCmd,
+		selenoidVerifyCmd,
+		selenoidUpgradeCmd,
+		selenoidRollbackCmd,
+		selenoidPruneCmd,
+		selenoidBrowsersListCmd,
+		selenoidBrowsersAddCmd,
+		selenoidBrowsersRemoveCmd,
+		selenoidReloadCmd,
 	} {
 		c.Flags().BoolVarP(&quiet, "quiet", "q", false, "suppress output")
 		c.Flags().BoolVarP(&useDrivers, "use-drivers", "d", false, "use drivers mode instead of Docker")
+		c.Flags().StringVarP(&instance, "instance", "", "", "named instance to work with (allows running several isolated instances on one host)")
+		c.Flags().StringVarP(&engine, "engine", "", selenoid.EngineAuto, "container engine: auto, docker or podman (Docker mode only)")
+		c.Flags().StringVarP(&dockerHost, "docker-host", "", "", "engine API endpoint overriding DOCKER_HOST and context, e.g. tcp://host:2376 or ssh://user@host (Docker mode only)")
+		c.Flags().StringVarP(&dockerContext, "context", "", "", "Docker CLI context to use instead of the current one (Docker mode only)")
 	}
+	selenoidListCmd.Flags().BoolVarP(&useDrivers, "use-drivers", "d", false, "use drivers mode instead of Docker")
+	selenoidListCmd.Flags().StringVarP(&engine, "engine", "", selenoid.EngineAuto, "container engine: auto, docker or podman (Docker mode only)")
+	selenoidListCmd.Flags().StringVarP(&dockerHost, "docker-host", "", "", "engine API endpoint overriding DOCKER_HOST and context, e.g. tcp://host:2376 or ssh://user@host (Docker mode only)")
+	selenoidListCmd.Flags().StringVarP(&dockerContext, "context", "", "", "Docker CLI context to use instead of the current one (Docker mode only)")
+	selenoidSuperviseCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "suppress output")
+	selenoidSuperviseCmd.Flags().StringVarP(&instance, "instance", "", "", "named instance to work with (allows running several isolated instances on one host)")
+	selenoidSuperviseCmd.Flags().StringVarP(&engine, "engine", "", selenoid.EngineAuto, "container engine: auto, docker or podman (Docker mode only)")
+	selenoidSuperviseCmd.Flags().StringVarP(&dockerHost, "docker-host", "", "", "engine API endpoint overriding DOCKER_HOST and context, e.g. tcp://host:2376 or ssh://user@host (Docker mode only)")
+	selenoidSuperviseCmd.Flags().StringVarP(&dockerContext, "context", "", "", "Docker CLI context to use instead of the current one (Docker mode only)")
 	for _, c := range []*cobra.Command{
 		selenoidDownloadCmd,
 		selenoidArgsCmd,
 		selenoidConfigureCmd,
 		selenoidStartCmd,
+		selenoidSystemdCmd,
 		selenoidStopCmd,
 		selenoidUpdateCmd,
 		selenoidCleanupCmd,
 		selenoidStatusCmd,
+		selenoidSuperviseCmd,
+		selenoidLogsCmd,
+		selenoidBundleInstallCmd,
+		selenoidVerifyCmd,
+		selenoidUpgradeCmd,
+		selenoidRollbackCmd,
+		selenoidPruneCmd,
+		selenoidBrowsersListCmd,
+		selenoidBrowsersAddCmd,
+		selenoidBrowsersRemoveCmd,
+		selenoidReloadCmd,
 	} {
 		c.Flags().StringVarP(&configDir, "config-dir", "c", selenoid.GetSelenoidConfigDir(), "directory to save files")
 		c.Flags().Uint16VarP(&port, "port", "p", selenoid.DefaultPort, "override listen port")
@@ -96,10 +173,12 @@ func initFlags() {
 		selenoidDownloadUICmd,
 		selenoidUIArgsCmd,
 		selenoidStartUICmd,
+		selenoidUISystemdCmd,
 		selenoidStopUICmd,
 		selenoidUpdateUICmd,
 		selenoidCleanupUICmd,
 		selenoidUIStatusCmd,
+		selenoidUILogsCmd,
 	} {
 		c.Flags().StringVarP(&uiConfigDir, "config-dir", "c", selenoid.GetSelenoidUIConfigDir(), "directory to save files")
 		c.Flags().Uint16VarP(&uiPort, "port", "p", selenoid.UIDefaultPort, "override listen port")
@@ -110,40 +189,64 @@ func initFlags() {
 		selenoidArgsCmd,
 		selenoidConfigureCmd,
 		selenoidStartCmd,
+		selenoidSystemdCmd,
 		selenoidUpdateCmd,
+		selenoidSuperviseCmd,
 		selenoidDownloadUICmd,
 		selenoidUIArgsCmd,
 		selenoidStartUICmd,
+		selenoidUISystemdCmd,
 		selenoidUpdateUICmd,
+		selenoidBundleCreateCmd,
+		selenoidUpgradeCmd,
 	} {
 		c.Flags().StringVarP(&operatingSystem, "operating-system", "o", runtime.GOOS, "target operating system (drivers only)")
 		c.Flags().StringVarP(&arch, "architecture", "a", runtime.GOARCH, "target architecture (drivers only)")
+		c.Flags().StringVarP(&cacheDir, "cache-dir", "", selenoid.GetCacheDir(), "directory to cache downloaded binaries and drivers (drivers only)")
 	}
 	for _, c := range []*cobra.Command{
 		selenoidDownloadCmd,
 		selenoidArgsCmd,
 		selenoidConfigureCmd,
 		selenoidStartCmd,
+		selenoidSystemdCmd,
 		selenoidUpdateCmd,
+		selenoidSuperviseCmd,
 		selenoidDownloadUICmd,
 		selenoidUIArgsCmd,
 		selenoidStartUICmd,
+		selenoidUISystemdCmd,
 		selenoidUpdateUICmd,
+		selenoidBundleCreateCmd,
+		selenoidUpgradeCmd,
 	} {
 		c.Flags().StringVarP(&version, "version", "v", selenoid.Latest, "desired version; default is latest release")
 		c.Flags().StringVarP(&registry, "registry", "r", selenoid.DefaultRegistryUrl, "Docker registry to use")
+		c.Flags().StringSliceVarP(&registryMirrors, "registry-mirror", "", nil, "registry mirror to try before the registry, can be repeated")
+		c.Flags().IntVarP(&retries, "retries", "", selenoid.DefaultRetryAttempts, "number of attempts for network operations")
+		c.Flags().DurationVarP(&retryBackoff, "retry-backoff", "", selenoid.DefaultRetryBackoff, "delay before retrying network operation, doubled after every attempt")
+		c.Flags().Float64VarP(&retryJitter, "retry-jitter", "", selenoid.DefaultRetryJitter, "random fraction of retry delay added to or subtracted from it")
 	}
 	for _, c := range []*cobra.Command{
 		selenoidConfigureCmd,
 		selenoidStartCmd,
+		selenoidSystemdCmd,
 		selenoidUpdateCmd,
+		selenoidSuperviseCmd,
+		selenoidBundleCreateCmd,
+		selenoidUpgradeCmd,
 	} {
 		c.Flags().StringVarP(&browsers, "browsers", "b", "", "semicolon separated list of browser names to process")
 		c.Flags().StringVarP(&browserEnv, "browser-env", "w", "", "override container or driver environment variables (e.g. \"KEY1=value1 KEY2=value2\")")
 		c.Flags().StringVarP(&browsersJson, "browsers-json", "j", "", "browsers JSON file to sync with")
+		c.Flags().StringVarP(&catalog, "catalog", "", "", "browser images catalog file or URL (Docker only)")
 		c.Flags().StringVarP(&driversInfoUrl, "drivers-info", "", selenoid.DefaultDriversInfoURL, "drivers info JSON data URL (in most cases never need to be set manually)")
 		c.Flags().BoolVarP(&skipDownload, "no-download", "n", false, "only output config file without downloading images or drivers")
-		c.Flags().IntVarP(&lastVersions, "last-versions", "l", 2, "process only last N versions (Docker only)")
+		c.Flags().IntVarP(&lastVersions, "last-versions", "l", 2, "process only last N stable versions (Docker only)")
+		c.Flags().StringVarP(&tagFilter, "tag-filter", "", "", "process only browser image tags matching regular expression (Docker only)")
+		c.Flags().BoolVarP(&prereleases, "include-prereleases", "", false, "also process prerelease tags like 120.0-beta (Docker only)")
+		c.Flags().IntVarP(&parallel, "parallel", "", 1, "pull up to N images in parallel (Docker only)")
+		c.Flags().BoolVarP(&pinDigests, "pin-digests", "", false, "pin browser images to repository digests in browsers.json (Docker only)")
 		c.Flags().IntVarP(&shmSize, "shm-size", "z", 0, "add shmSize sized in megabytes (Docker only)")
 		c.Flags().IntVarP(&tmpfs, "tmpfs", "t", 0, "add tmpfs volume sized in megabytes (Docker only)")
 		c.Flags().BoolVarP(&vnc, "vnc", "s", false, "download containers with VNC support (Docker only)")
@@ -153,6 +256,7 @@ func initFlags() {
 		selenoidArgsCmd,
 		selenoidConfigureCmd,
 		selenoidStartCmd,
+		selenoidSuperviseCmd,
 		selenoidDownloadUICmd,
 		selenoidUIArgsCmd,
 		selenoidStartUICmd,
@@ -161,22 +265,155 @@ func initFlags() {
 	}
 	for _, c := range []*cobra.Command{
 		selenoidStopCmd,
+		selenoidSuperviseCmd,
 		selenoidStopUICmd,
 	} {
 		c.Flags().BoolVarP(&graceful, "graceful", "", false, "do action gracefully (e.g. gracefully stop Selenoid)")
 		c.Flags().DurationVarP(&gracefulTimeout, "graceful-timeout", "", 30*time.Second, "graceful timeout value (how much time to wait for graceful action execution)")
 	}
+	for _, c := range []*cobra.Command{
+		selenoidStartCmd,
+		selenoidStopCmd,
+		selenoidUpdateCmd,
+		selenoidCleanupCmd,
+		selenoidStatusCmd,
+		selenoidSuperviseCmd,
+		selenoidStartUICmd,
+		selenoidStopUICmd,
+		selenoidUpdateUICmd,
+		selenoidCleanupUICmd,
+		selenoidUIStatusCmd,
+		selenoidReloadCmd,
+	} {
+		c.Flags().BoolVarP(&adopt, "adopt", "", false, "find processes started without cm by executable name (drivers only)")
+	}
+	for _, c := range []*cobra.Command{
+		selenoidStatusCmd,
+		selenoidUIStatusCmd,
+		selenoidVerifyCmd,
+		selenoidBrowsersListCmd,
+	} {
+		c.Flags().StringVarP(&outputFormat, "output", "", "text", "output format: text, json or yaml")
+	}
 	for _, c := range []*cobra.Command{
 		selenoidStartCmd,
 		selenoidUpdateCmd,
 		selenoidStartUICmd,
 		selenoidUpdateUICmd,
+		selenoidUpgradeCmd,
+		selenoidRollbackCmd,
+	} {
+		c.Flags().StringVarP(&args, "args", "g", "", "additional service arguments (e.g. \"-limit 5\")")
+		c.Flags().StringVarP(&env, "env", "e", "", "override service environment variables (e.g. \"KEY1=value1 KEY2=value2\")")
+		c.Flags().StringVarP(&userNS, "userns", "", "", "override user namespace, similarly to \"docker run --userns host ...\" (Docker only)")
+		c.Flags().BoolVarP(&disableLogs, "disable-logs", "", false, "start with log saving feature disabled")
+		c.Flags().DurationVarP(&waitTimeout, "wait-timeout", "", 30*time.Second, "how much time to wait for service to become ready (0 to disable)")
+		c.Flags().BoolVarP(&detach, "detach", "", false, "run in background writing output to rotated log files (drivers only)")
+	}
+	selenoidExportCmd.Flags().StringVarP(&instance, "instance", "", "", "named instance to work with (allows running several isolated instances on one host)")
+	selenoidExportCmd.Flags().StringVarP(&engine, "engine", "", selenoid.EngineAuto, "container engine: auto, docker or podman (Docker mode only)")
+	selenoidExportCmd.Flags().StringVarP(&dockerHost, "docker-host", "", "", "engine API endpoint overriding DOCKER_HOST and context, e.g. tcp://host:2376 or ssh://user@host (Docker mode only)")
+	selenoidExportCmd.Flags().StringVarP(&dockerContext, "context", "", "", "Docker CLI context to use instead of the current one (Docker mode only)")
+	selenoidExportCmd.Flags().StringVarP(&configDir, "config-dir", "c", selenoid.GetSelenoidConfigDir(), "directory to save files")
+	selenoidExportCmd.Flags().Uint16VarP(&port, "port", "p", selenoid.DefaultPort, "override listen port")
+	selenoidExportCmd.Flags().Uint16VarP(&uiPort, "ui-port", "", selenoid.UIDefaultPort, "override Selenoid UI listen port")
+	selenoidExportCmd.Flags().StringVarP(&version, "version", "v", selenoid.Latest, "desired version; default is latest release")
+	selenoidExportCmd.Flags().StringVarP(&uiVersion, "ui-version", "", selenoid.Latest, "desired Selenoid UI version; default is latest release")
+	selenoidExportCmd.Flags().StringVarP(&registry, "registry", "r", selenoid.DefaultRegistryUrl, "Docker registry to use")
+	selenoidExportCmd.Flags().StringSliceVarP(&registryMirrors, "registry-mirror", "", nil, "registry mirror to try before the registry, can be repeated")
+	selenoidExportCmd.Flags().StringVarP(&args, "args", "g", "", "additional service arguments (e.g. \"-limit 5\")")
+	selenoidExportCmd.Flags().StringVarP(&env, "env", "e", "", "override service environment variables (e.g. \"KEY1=value1 KEY2=value2\")")
+	selenoidExportCmd.Flags().StringVarP(&userNS, "userns", "", "", "override user namespace, similarly to \"docker run --userns host ...\" (Docker only)")
+	selenoidExportCmd.Flags().BoolVarP(&disableLogs, "disable-logs", "", false, "start with log saving feature disabled")
+	selenoidExportCmd.Flags().StringVarP(&exportFormat, "format", "", selenoid.ExportCompose, "output format: compose, k8s or docker-run")
+	for _, c := range []*cobra.Command{
+		selenoidSystemdCmd,
+		selenoidUISystemdCmd,
 	} {
 		c.Flags().StringVarP(&args, "args", "g", "", "additional service arguments (e.g. \"-limit 5\")")
 		c.Flags().StringVarP(&env, "env", "e", "", "override service environment variables (e.g. \"KEY1=value1 KEY2=value2\")")
 		c.Flags().StringVarP(&userNS, "userns", "", "", "override user namespace, similarly to \"docker run --userns host ...\" (Docker only)")
 		c.Flags().BoolVarP(&disableLogs, "disable-logs", "", false, "start with log saving feature disabled")
+		c.Flags().BoolVarP(&installUnit, "install", "", false, "save unit file to directory instead of printing it")
+		c.Flags().StringVarP(&installUnitDir, "install-dir", "", "", "directory to save unit file to (default "+selenoid.DefaultSystemdUnitDir+" or ~/.config/systemd/user for rootless engines)")
+	}
+	selenoidSuperviseCmd.Flags().StringVarP(&args, "args", "g", "", "additional service arguments (e.g. \"-limit 5\")")
+	selenoidSuperviseCmd.Flags().StringVarP(&env, "env", "e", "", "override service environment variables (e.g. \"KEY1=value1 KEY2=value2\")")
+	selenoidSuperviseCmd.Flags().BoolVarP(&disableLogs, "disable-logs", "", false, "start with log saving feature disabled")
+	for _, c := range []*cobra.Command{
+		selenoidLogsCmd,
+		selenoidUILogsCmd,
+	} {
+		c.Flags().BoolVarP(&follow, "follow", "f", false, "follow log output")
+		c.Flags().IntVarP(&tailLogs, "tail", "", 0, "number of lines to show from the end of the logs (0 to show all)")
+		c.Flags().StringVarP(&since, "since", "", "", "show logs since timestamp (e.g. 2006-01-02T15:04:05Z) or relative duration (e.g. 10m)")
+		c.Flags().BoolVarP(&timestamps, "timestamps", "t", false, "show timestamps (Docker only, drivers mode log lines already have them)")
+	}
+	selenoidLogsCmd.Flags().BoolVarP(&listSessions, "sessions", "", false, "list saved browser session logs")
+	selenoidLogsCmd.Flags().StringVarP(&session, "session", "", "", "show saved log of browser session with this ID")
+	selenoidBundleCreateCmd.Flags().StringVarP(&bundleOutput, "output", "", "selenoid-bundle.tar.gz", "bundle file to create")
+	selenoidBundleCreateCmd.Flags().BoolVarP(&bundleUI, "ui", "", false, "include Selenoid UI to bundle")
+	selenoidBundleCreateCmd.Flags().StringVarP(&uiVersion, "ui-version", "", selenoid.Latest, "desired Selenoid UI version; default is latest release")
+	selenoidBundleInstallCmd.Flags().StringVarP(&uiConfigDir, "ui-config-dir", "", selenoid.GetSelenoidUIConfigDir(), "directory to save Selenoid UI files")
+	selenoidBundleInstallCmd.Flags().StringVarP(&cacheDir, "cache-dir", "", selenoid.GetCacheDir(), "directory to cache downloaded binaries and drivers (drivers only)")
+	for _, c := range []*cobra.Command{
+		selenoidUpgradeCmd,
+		selenoidRollbackCmd,
+	} {
+		c.Flags().StringVarP(&uiConfigDir, "ui-config-dir", "", selenoid.GetSelenoidUIConfigDir(), "Selenoid UI configuration directory")
+		c.Flags().Uint16VarP(&uiPort, "ui-port", "", selenoid.UIDefaultPort, "override Selenoid UI listen port")
+	}
+	selenoidUpgradeCmd.Flags().StringVarP(&uiVersion, "ui-version", "", selenoid.Latest, "desired Selenoid UI version; default is latest release")
+	selenoidUpgradeCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "upgrade without asking for confirmation")
+	selenoidPruneCmd.Flags().StringVarP(&browsers, "browsers", "b", "", "semicolon separated list of browser names to process")
+	selenoidPruneCmd.Flags().StringVarP(&catalog, "catalog", "", "", "browser images catalog file or URL (Docker only)")
+	selenoidPruneCmd.Flags().StringVarP(&registry, "registry", "r", selenoid.DefaultRegistryUrl, "Docker registry to use")
+	selenoidPruneCmd.Flags().StringSliceVarP(&registryMirrors, "registry-mirror", "", nil, "registry mirror to try before the registry, can be repeated")
+	selenoidBrowsersListCmd.Flags().StringVarP(&browsers, "browsers", "b", "", "semicolon separated list of browser names and version constraints to preview (e.g. \"chrome:>=120.0;firefox\")")
+	selenoidBrowsersListCmd.Flags().StringVarP(&catalog, "catalog", "", "", "browser images catalog file or URL (Docker only)")
+	selenoidBrowsersListCmd.Flags().StringVarP(&registry, "registry", "r", selenoid.DefaultRegistryUrl, "Docker registry to use")
+	selenoidBrowsersListCmd.Flags().StringSliceVarP(&registryMirrors, "registry-mirror", "", nil, "registry mirror to try before the registry, can be repeated")
+	selenoidBrowsersListCmd.Flags().IntVarP(&lastVersions, "last-versions", "l", 2, "preview only last N stable versions")
+	selenoidBrowsersListCmd.Flags().StringVarP(&tagFilter, "tag-filter", "", "", "preview only browser image tags matching regular expression")
+	selenoidBrowsersListCmd.Flags().BoolVarP(&prereleases, "include-prereleases", "", false, "also show prerelease tags like 120.0-beta")
+	selenoidBrowsersListCmd.Flags().IntVarP(&retries, "retries", "", selenoid.DefaultRetryAttempts, "number of attempts for network operations")
+	selenoidBrowsersListCmd.Flags().DurationVarP(&retryBackoff, "retry-backoff", "", selenoid.DefaultRetryBackoff, "delay before retrying network operation, doubled after every attempt")
+	selenoidBrowsersListCmd.Flags().Float64VarP(&retryJitter, "retry-jitter", "", selenoid.DefaultRetryJitter, "random fraction of retry delay added to or subtracted from it")
+	selenoidBrowsersListCmd.Flags().BoolVarP(&browsersRemote, "remote", "", false, "also fetch versions available in registry")
+	selenoidBrowsersAddCmd.Flags().StringVarP(&catalog, "catalog", "", "", "browser images catalog file or URL (Docker only)")
+	selenoidBrowsersAddCmd.Flags().StringVarP(&registry, "registry", "r", selenoid.DefaultRegistryUrl, "Docker registry to use")
+	selenoidBrowsersAddCmd.Flags().StringSliceVarP(&registryMirrors, "registry-mirror", "", nil, "registry mirror to try before the registry, can be repeated")
+	selenoidBrowsersAddCmd.Flags().IntVarP(&retries, "retries", "", selenoid.DefaultRetryAttempts, "number of attempts for network operations")
+	selenoidBrowsersAddCmd.Flags().DurationVarP(&retryBackoff, "retry-backoff", "", selenoid.DefaultRetryBackoff, "delay before retrying network operation, doubled after every attempt")
+	selenoidBrowsersAddCmd.Flags().Float64VarP(&retryJitter, "retry-jitter", "", selenoid.DefaultRetryJitter, "random fraction of retry delay added to or subtracted from it")
+	selenoidBrowsersAddCmd.Flags().StringVarP(&tagFilter, "tag-filter", "", "", "choose newest version among browser image tags matching regular expression")
+	selenoidBrowsersAddCmd.Flags().BoolVarP(&prereleases, "include-prereleases", "", false, "choose newest version among prerelease tags too")
+	selenoidBrowsersAddCmd.Flags().StringVarP(&browserEnv, "browser-env", "w", "", "override container environment variables (e.g. \"KEY1=value1 KEY2=value2\")")
+	selenoidBrowsersAddCmd.Flags().BoolVarP(&skipDownload, "no-download", "n", false, "only edit browsers.json without pulling images")
+	selenoidBrowsersAddCmd.Flags().IntVarP(&parallel, "parallel", "", 1, "pull up to N images in parallel")
+	selenoidBrowsersAddCmd.Flags().BoolVarP(&pinDigests, "pin-digests", "", false, "pin added browser images to repository digests")
+	selenoidBrowsersAddCmd.Flags().IntVarP(&shmSize, "shm-size", "z", 0, "add shmSize sized in megabytes")
+	selenoidBrowsersAddCmd.Flags().IntVarP(&tmpfs, "tmpfs", "t", 0, "add tmpfs volume sized in megabytes")
+	selenoidBrowsersAddCmd.Flags().BoolVarP(&browsersDefault, "default", "", false, "make added versions default")
+	for _, c := range []*cobra.Command{
+		selenoidConfigureCmd,
+		selenoidBrowsersAddCmd,
+		selenoidBrowsersRemoveCmd,
+	} {
+		c.Flags().BoolVarP(&reloadConfig, "reload", "", false, "make running Selenoid apply new browsers.json without restart")
 	}
+	selenoidPruneCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "", false, "only show unused browser images without removing them")
+	for _, c := range []*cobra.Command{
+		selenoidPruneCmd,
+		selenoidCleanupCmd,
+	} {
+		c.Flags().IntVarP(&pruneKeep, "keep", "", 0, "keep N most recent unused images of every browser")
+	}
+	selenoidCleanupCmd.Flags().BoolVarP(&cleanupPrune, "prune", "", false, "also remove browser images not referenced by browsers.json (Docker only)")
+	selenoidCleanupCmd.Flags().BoolVarP(&cleanupImages, "images", "", false, "also remove browser images from browsers.json and video recorder image (Docker only)")
+	selenoidCleanupCmd.Flags().BoolVarP(&cleanupNetwork, "network", "", false, "also remove selenoid network (Docker only)")
+	selenoidCleanupCmd.Flags().BoolVarP(&cleanupKeepVideos, "keep-videos", "", false, "do not remove recorded videos")
+	selenoidCleanupCmd.Flags().BoolVarP(&cleanupKeepLogs, "keep-logs", "", false, "do not remove saved session logs")
 }
 
 func createLifecycle(configDir string, port uint16) (*selenoid.Lifecycle, error) {
@@ -185,32 +422,61 @@ func createLifecycle(configDir string, port uint16) (*selenoid.Lifecycle, error)
 		Force:           force,
 		Graceful:        graceful,
 		GracefulTimeout: gracefulTimeout,
-		ConfigDir:       configDir,
+		ConfigDir:       instanceConfigDir(configDir),
+		Instance:        instance,
+		Retry:           selenoid.RetryPolicy{Attempts: retries, Backoff: retryBackoff, Jitter: retryJitter},
 		UseDrivers:      useDrivers,
 		Browsers:        browsers,
-		BrowserEnv:      browserEnv,
+		BrowserEnv:      strings.Fields(browserEnv),
 		Download:        !skipDownload,
 		Args:            args,
-		Env:             env,
+		Env:             strings.Fields(env),
 		Port:            int(port),
 		DisableLogs:     disableLogs,
+		WaitTimeout:     waitTimeout,
 
-		LastVersions: lastVersions,
-		RegistryUrl:  registry,
-		BrowsersJson: browsersJson,
-		ShmSize:      shmSize,
-		Tmpfs:        tmpfs,
-		VNC:          vnc,
-		UserNS:       userNS,
+		LastVersions:       lastVersions,
+		TagFilter:          tagFilter,
+		IncludePrereleases: prereleases,
+		RegistryUrl:        registry,
+		RegistryMirrors:    registryMirrors,
+		BrowsersJson:       browsersJson,
+		Catalog:            catalog,
+		Parallel:           parallel,
+		PinDigests:         pinDigests,
+		ShmSize:            shmSize,
+		Tmpfs:              tmpfs,
+		VNC:                vnc,
+		UserNS:             userNS,
+		Engine:             engine,
+		DockerHost:         dockerHost,
+		Context:            dockerContext,
 
 		DriversInfoUrl: driversInfoUrl,
+		CacheDir:       cacheDir,
 		OS:             operatingSystem,
 		Arch:           arch,
+		Adopt:          adopt,
+		Detach:         detach,
 		Version:        version,
 	}
 	return selenoid.NewLifecycle(&config)
 }
 
+// instanceConfigDir replaces default configuration directory with named instance one
+func instanceConfigDir(configDir string) string {
+	if instance == "" {
+		return configDir
+	}
+	switch configDir {
+	case selenoid.GetSelenoidConfigDir():
+		return selenoid.GetSelenoidInstanceConfigDir(instance)
+	case selenoid.GetSelenoidUIConfigDir():
+		return selenoid.GetSelenoidUIInstanceConfigDir(instance)
+	}
+	return configDir
+}
+
 var selenoidCmd = &cobra.Command{
 	Use:   "selenoid",
 	Short: "Download, configure and run Selenoid",
@@ -220,5 +486,5 @@ var selenoidCmd = &cobra.Command{
 }
 
 func stderr(format string, a ...interface{}) {
-	_, _ = fmt.Fprintf(os.Stderr, format, a)
+	_, _ = fmt.Fprintf(os.Stderr, format, a...)
 }
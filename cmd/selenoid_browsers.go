@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	browsersRemote  bool
+	browsersDefault bool
+)
+
+var selenoidBrowsersCmd = &cobra.Command{
+	Use:   "browsers",
+	Short: "Explore browser versions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Usage()
+	},
+}
+
+var selenoidBrowsersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List browser versions available in registry, pulled locally and used in browsers.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		if outputFormat != outputText {
+			quiet = true
+		}
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		browsers, err := lifecycle.ListBrowsers(selenoid.BrowsersListOptions{Remote: browsersRemote})
+		lifecycle.Close()
+		if err != nil {
+			stderr("Failed to list browsers: %v\n", err)
+			os.Exit(1)
+		}
+		switch outputFormat {
+		case outputText:
+			lifecycle.PrintBrowsers(browsers)
+		case outputJSON:
+			data, _ := json.MarshalIndent(browsers, "", "    ")
+			fmt.Println(string(data))
+		case outputYAML:
+			data, _ := yaml.Marshal(browsers)
+			fmt.Print(string(data))
+		default:
+			stderr("Unsupported output format: %s\n", outputFormat)
+			os.Exit(1)
+		}
+	},
+}
+
+var selenoidBrowsersAddCmd = &cobra.Command{
+	Use:   "add browser[:version]...",
+	Short: "Add browser versions to browsers.json keeping other entries as is",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		editBrowsers(args, func(lc *selenoid.Lifecycle, opts selenoid.BrowsersEditOptions) error {
+			return lc.AddBrowsers(opts)
+		})
+	},
+}
+
+var selenoidBrowsersRemoveCmd = &cobra.Command{
+	Use:   "remove browser[:version]...",
+	Short: "Remove browser versions from browsers.json keeping other entries as is",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		editBrowsers(args, func(lc *selenoid.Lifecycle, opts selenoid.BrowsersEditOptions) error {
+			return lc.RemoveBrowsers(opts)
+		})
+	},
+}
+
+func editBrowsers(browsers []string, editAction func(*selenoid.Lifecycle, selenoid.BrowsersEditOptions) error) {
+	lifecycle, err := createLifecycle(configDir, port)
+	if err != nil {
+		stderr("Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	defer lifecycle.Close()
+	err = editAction(lifecycle, selenoid.BrowsersEditOptions{Browsers: browsers, SetDefault: browsersDefault})
+	if err != nil {
+		stderr("Failed to edit browsers: %v\n", err)
+		os.Exit(1)
+	}
+	if reloadConfig && reloadImpl(lifecycle) != nil {
+		os.Exit(1)
+	}
+}
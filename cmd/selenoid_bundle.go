@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleOutput string
+	bundleUI     bool
+)
+
+var selenoidBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and install offline bundles for air-gapped hosts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Usage()
+	},
+}
+
+var selenoidBundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Download Selenoid, browsers and configuration to a single archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer lifecycle.Close()
+		f, err := os.Create(bundleOutput)
+		if err != nil {
+			stderr("Failed to create bundle: %v\n", err)
+			os.Exit(1)
+		}
+		err = lifecycle.CreateBundle(f, selenoid.BundleOptions{UI: bundleUI, UIVersion: uiVersion})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(bundleOutput)
+			stderr("Failed to create bundle: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var selenoidBundleInstallCmd = &cobra.Command{
+	Use:   "install <bundle>",
+	Short: "Install bundle created with bundle create without network access",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer lifecycle.Close()
+		f, err := os.Open(args[0])
+		if err != nil {
+			stderr("Failed to open bundle: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		err = lifecycle.InstallBundle(f, selenoid.BundleOptions{UIConfigDir: instanceConfigDir(uiConfigDir)})
+		if err != nil {
+			stderr("Failed to install bundle: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -2,22 +2,45 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/aerokube/cm/selenoid"
 	"github.com/spf13/cobra"
 )
 
+var (
+	cleanupPrune      bool
+	cleanupImages     bool
+	cleanupNetwork    bool
+	cleanupKeepVideos bool
+	cleanupKeepLogs   bool
+)
+
 var selenoidCleanupCmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Remove Selenoid traces",
 	Run: func(cmd *cobra.Command, args []string) {
-		cleanupImpl(configDir, port, func(lc *selenoid.Lifecycle) error {
-			return lc.Stop()
+		opts := selenoid.CleanupOptions{
+			Images:     cleanupImages,
+			Network:    cleanupNetwork,
+			KeepVideos: cleanupKeepVideos,
+			KeepLogs:   cleanupKeepLogs,
+		}
+		cleanupImpl(configDir, port, opts, func(lc *selenoid.Lifecycle) error {
+			err := lc.Stop()
+			if err != nil || !cleanupPrune {
+				return err
+			}
+			// Prune before configuration directory with browsers.json is removed
+			if err := pruneImages(lc); err != nil {
+				lc.Errorf("Failed to prune images: %v", err)
+			}
+			return nil
 		})
 	},
 }
 
-func cleanupImpl(configDir string, port uint16, stopAction func(*selenoid.Lifecycle) error) {
+func cleanupImpl(configDir string, port uint16, opts selenoid.CleanupOptions, stopAction func(*selenoid.Lifecycle) error) {
 	lifecycle, err := createLifecycle(configDir, port)
 	if err != nil {
 		stderr("Failed to initialize: %v\n", err)
@@ -30,11 +53,15 @@ func cleanupImpl(configDir string, port uint16, stopAction func(*selenoid.Lifecy
 		os.Exit(1)
 	}
 
-	err = os.RemoveAll(configDir)
+	report, err := lifecycle.Cleanup(opts)
 	if err != nil {
-		lifecycle.Errorf("Failed to remove configuration directory: %v\n", err)
+		lifecycle.Errorf("Failed to clean up: %v\n", err)
 		os.Exit(1)
 	}
-	lifecycle.Titlef("Successfully removed configuration directory\n")
+	if instance != "" {
+		// Instance directory is removed only when both Selenoid and Selenoid UI were cleaned up
+		_ = os.Remove(filepath.Dir(lifecycle.Config.ConfigDir))
+	}
+	lifecycle.PrintCleanupReport(report)
 	os.Exit(0)
 }
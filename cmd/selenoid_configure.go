@@ -20,6 +20,9 @@ var selenoidConfigureCmd = &cobra.Command{
 			lifecycle.Errorf("Failed to configure Selenoid: %v\n", err)
 			os.Exit(1)
 		}
+		if reloadConfig && reloadImpl(lifecycle) != nil {
+			os.Exit(1)
+		}
 		os.Exit(0)
 	},
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	uiVersion    string
+)
+
+var selenoidExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export Selenoid and Selenoid UI deployment as docker-compose.yml, Kubernetes manifests or docker run commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		quiet = true
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer lifecycle.Close()
+		err = lifecycle.Export(os.Stdout, selenoid.ExportOptions{
+			Format:    exportFormat,
+			UIPort:    int(uiPort),
+			UIVersion: uiVersion,
+		})
+		if err != nil {
+			stderr("Failed to export: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+const defaultInstanceName = "default"
+
+var selenoidListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Selenoid instances",
+	Run: func(cmd *cobra.Command, args []string) {
+		instances, err := selenoid.ListInstances()
+		if err != nil {
+			stderr("Failed to list instances: %v\n", err)
+			os.Exit(1)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, "INSTANCE\tSELENOID\tSELENOID UI\tCONFIG DIR")
+		for _, name := range append([]string{""}, instances...) {
+			selenoidStatus, uiStatus, err := instanceStatus(name)
+			if err != nil {
+				stderr("Failed to get instance status: %v\n", err)
+				os.Exit(1)
+			}
+			if name == "" {
+				name = defaultInstanceName
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, formatState(selenoidStatus), formatState(uiStatus), selenoidStatus.ConfigDir)
+		}
+		_ = w.Flush()
+		os.Exit(0)
+	},
+}
+
+func instanceStatus(name string) (*selenoid.Status, *selenoid.Status, error) {
+	lifecycle, err := selenoid.NewLifecycle(&selenoid.LifecycleConfig{
+		Quiet:      true,
+		Instance:   name,
+		ConfigDir:  selenoid.GetSelenoidInstanceConfigDir(name),
+		UseDrivers: useDrivers,
+		Engine:     engine,
+		DockerHost: dockerHost,
+		Context:    dockerContext,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer lifecycle.Close()
+	return lifecycle.Status(), lifecycle.UIStatus(), nil
+}
+
+func formatState(status *selenoid.Status) string {
+	if status.IsRunning() && status.Port != 0 {
+		return fmt.Sprintf("%s (port %d)", status.State, status.Port)
+	}
+	return string(status.State)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	follow       bool
+	tailLogs     int
+	since        string
+	timestamps   bool
+	listSessions bool
+	session      string
+)
+
+var selenoidLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show Selenoid logs",
+	Run: func(cmd *cobra.Command, args []string) {
+		logsImpl(configDir, port, func(lc *selenoid.Lifecycle, opts selenoid.LogsOptions) error {
+			switch {
+			case listSessions:
+				return printSessionLogs(lc)
+			case session != "":
+				return lc.SessionLog(os.Stdout, session)
+			}
+			return lc.Logs(os.Stdout, opts)
+		})
+	},
+}
+
+func logsImpl(configDir string, port uint16, logsAction func(*selenoid.Lifecycle, selenoid.LogsOptions) error) {
+	quiet = true
+	sinceTime, err := selenoid.ParseSince(since)
+	if err != nil {
+		stderr("Failed to show logs: %v\n", err)
+		os.Exit(1)
+	}
+	lifecycle, err := createLifecycle(configDir, port)
+	if err != nil {
+		stderr("Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	defer lifecycle.Close()
+	err = logsAction(lifecycle, selenoid.LogsOptions{
+		Tail:       tailLogs,
+		Follow:     follow,
+		Since:      sinceTime,
+		Timestamps: timestamps,
+	})
+	if err != nil {
+		stderr("Failed to show logs: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printSessionLogs(lc *selenoid.Lifecycle) error {
+	logs, err := lc.ListSessionLogs()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SESSION\tSIZE\tMODIFIED")
+	for _, l := range logs {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", l.Session, l.Size, l.Modified.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
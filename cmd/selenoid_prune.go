@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneDryRun bool
+	pruneKeep   int
+)
+
+var selenoidPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove browser images not referenced by browsers.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer lifecycle.Close()
+		err = pruneImages(lifecycle)
+		if err != nil {
+			stderr("Failed to prune images: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func pruneImages(lifecycle *selenoid.Lifecycle) error {
+	lifecycle.Titlef("Pruning unused browser images...")
+	report, err := lifecycle.Prune(selenoid.PruneOptions{DryRun: pruneDryRun, Keep: pruneKeep})
+	if err != nil {
+		return err
+	}
+	lifecycle.PrintPruneReport(report)
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var reloadConfig bool
+
+var selenoidReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Make running Selenoid apply browsers.json without restart",
+	Run: func(cmd *cobra.Command, args []string) {
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer lifecycle.Close()
+		err = reloadImpl(lifecycle)
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func reloadImpl(lifecycle *selenoid.Lifecycle) error {
+	err := lifecycle.Reload()
+	if err != nil {
+		lifecycle.Errorf("Failed to reload: %v\n", err)
+	}
+	return err
+}
@@ -1,20 +1,65 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 
+	"github.com/aerokube/cm/selenoid"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+
+	exitCodeStopped       = 3
+	exitCodeNotConfigured = 4
+)
+
+var outputFormat string
+
 var selenoidStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Shows Selenoid configuration status",
 	Run: func(cmd *cobra.Command, args []string) {
-		lifecycle, err := createLifecycle(configDir, port)
-		if err != nil {
-			stderr("Failed to initialize: %v\n", err)
-			os.Exit(1)
-		}
-		lifecycle.Status()
+		statusImpl(configDir, port, func(lc *selenoid.Lifecycle) *selenoid.Status {
+			return lc.Status()
+		})
 	},
 }
+
+func statusImpl(configDir string, port uint16, statusAction func(*selenoid.Lifecycle) *selenoid.Status) {
+	if outputFormat != outputText {
+		quiet = true
+	}
+	lifecycle, err := createLifecycle(configDir, port)
+	if err != nil {
+		stderr("Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	status := statusAction(lifecycle)
+	lifecycle.Close()
+	switch outputFormat {
+	case outputText:
+		lifecycle.PrintStatus(status)
+	case outputJSON:
+		data, _ := json.MarshalIndent(status, "", "    ")
+		fmt.Println(string(data))
+	case outputYAML:
+		data, _ := yaml.Marshal(status)
+		fmt.Print(string(data))
+	default:
+		stderr("Unsupported output format: %s\n", outputFormat)
+		os.Exit(1)
+	}
+	switch status.State {
+	case selenoid.StateStopped:
+		os.Exit(exitCodeStopped)
+	case selenoid.StateNotConfigured:
+		os.Exit(exitCodeNotConfigured)
+	}
+	os.Exit(0)
+}
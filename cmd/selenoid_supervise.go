@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var selenoidSuperviseCmd = &cobra.Command{
+	Use:   "supervise",
+	Short: "Run Selenoid in foreground and restart it when it exits (drivers only)",
+	Run: func(cmd *cobra.Command, args []string) {
+		useDrivers = true
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		err = lifecycle.Supervise(stopOnSignal())
+		if err != nil {
+			lifecycle.Errorf("Failed to supervise: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+func stopOnSignal() <-chan struct{} {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stop := make(chan struct{})
+	go func() {
+		<-signals
+		close(stop)
+	}()
+	return stop
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installUnit    bool
+	installUnitDir string
+)
+
+var selenoidSystemdCmd = &cobra.Command{
+	Use:   "systemd",
+	Short: "Generate systemd unit file for Selenoid",
+	Run: func(cmd *cobra.Command, args []string) {
+		systemdImpl(configDir, port, func(lc *selenoid.Lifecycle) (*selenoid.SystemdUnit, error) {
+			return lc.SystemdUnit()
+		})
+	},
+}
+
+func systemdImpl(configDir string, port uint16, unitAction func(*selenoid.Lifecycle) (*selenoid.SystemdUnit, error)) {
+	if !installUnit {
+		quiet = true
+	}
+	lifecycle, err := createLifecycle(configDir, port)
+	if err != nil {
+		stderr("Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	defer lifecycle.Close()
+	unit, err := unitAction(lifecycle)
+	if err != nil {
+		lifecycle.Errorf("Failed to generate unit file: %v\n", err)
+		os.Exit(1)
+	}
+	if !installUnit {
+		err = unit.Render(os.Stdout)
+		if err != nil {
+			stderr("Failed to render unit file: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	dir := installUnitDir
+	if dir == "" {
+		dir = unit.DefaultDir()
+	}
+	path, err := unit.Install(dir)
+	if err != nil {
+		lifecycle.Errorf("Failed to install unit file: %v\n", err)
+		os.Exit(1)
+	}
+	lifecycle.Titlef("Unit file saved to %s", path)
+	if unit.User {
+		lifecycle.Pointf("To start it type: systemctl --user daemon-reload && systemctl --user enable --now %s", unit.Name)
+		return
+	}
+	lifecycle.Pointf("To start it type: systemctl daemon-reload && systemctl enable --now %s", unit.Name)
+}
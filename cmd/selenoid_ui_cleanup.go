@@ -9,7 +9,7 @@ var selenoidCleanupUICmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Remove Selenoid UI traces",
 	Run: func(cmd *cobra.Command, args []string) {
-		cleanupImpl(uiConfigDir, uiPort, func(lc *selenoid.Lifecycle) error {
+		cleanupImpl(uiConfigDir, uiPort, selenoid.CleanupOptions{UI: true}, func(lc *selenoid.Lifecycle) error {
 			return lc.StopUI()
 		})
 	},
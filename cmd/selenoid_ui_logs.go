@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var selenoidUILogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show Selenoid UI logs",
+	Run: func(cmd *cobra.Command, args []string) {
+		logsImpl(uiConfigDir, uiPort, func(lc *selenoid.Lifecycle, opts selenoid.LogsOptions) error {
+			return lc.UILogs(os.Stdout, opts)
+		})
+	},
+}
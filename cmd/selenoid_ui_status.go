@@ -1,8 +1,7 @@
 package cmd
 
 import (
-	"os"
-
+	"github.com/aerokube/cm/selenoid"
 	"github.com/spf13/cobra"
 )
 
@@ -10,11 +9,8 @@ var selenoidUIStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Shows Selenoid UI status",
 	Run: func(cmd *cobra.Command, args []string) {
-		lifecycle, err := createLifecycle(uiConfigDir, uiPort)
-		if err != nil {
-			stderr("Failed to initialize: %v\n", err)
-			os.Exit(1)
-		}
-		lifecycle.UIStatus()
+		statusImpl(uiConfigDir, uiPort, func(lc *selenoid.Lifecycle) *selenoid.Status {
+			return lc.UIStatus()
+		})
 	},
 }
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var selenoidUISystemdCmd = &cobra.Command{
+	Use:   "systemd",
+	Short: "Generate systemd unit file for Selenoid UI",
+	Run: func(cmd *cobra.Command, args []string) {
+		systemdImpl(uiConfigDir, uiPort, func(lc *selenoid.Lifecycle) (*selenoid.SystemdUnit, error) {
+			return lc.UISystemdUnit()
+		})
+	},
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var assumeYes bool
+
+var selenoidUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Show what will change, then upgrade Selenoid, Selenoid UI and browsers keeping a snapshot for rollback",
+	Run: func(cmd *cobra.Command, args []string) {
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer lifecycle.Close()
+		uiLifecycle, err := createUILifecycle()
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer uiLifecycle.Close()
+
+		plan, err := lifecycle.PlanUpgrade()
+		if err != nil {
+			lifecycle.Errorf("Failed to plan upgrade: %v", err)
+			os.Exit(1)
+		}
+		var uiPlan *selenoid.UpgradePlan
+		if uiLifecycle.UIStatus().State != selenoid.StateNotConfigured {
+			uiPlan, err = uiLifecycle.PlanUIUpgrade()
+			if err != nil {
+				lifecycle.Errorf("Failed to plan upgrade: %v", err)
+				os.Exit(1)
+			}
+		}
+		lifecycle.Titlef("Upgrade plan:")
+		lifecycle.PrintUpgradePlan(plan)
+		if uiPlan != nil {
+			lifecycle.PrintUpgradePlan(uiPlan)
+		}
+		if plan.Empty() && (uiPlan == nil || uiPlan.Empty()) {
+			lifecycle.Titlef("Everything is up to date")
+			os.Exit(0)
+		}
+		if !assumeYes && !confirm("Proceed with upgrade?") {
+			lifecycle.Titlef("Upgrade cancelled")
+			os.Exit(0)
+		}
+
+		snapshotID := selenoid.NewSnapshotID()
+		if !plan.Empty() {
+			err = lifecycle.Upgrade(plan, snapshotID)
+			if err != nil {
+				lifecycle.Errorf("Failed to upgrade: %v", err)
+				os.Exit(1)
+			}
+		}
+		if uiPlan != nil && !uiPlan.Empty() {
+			err = uiLifecycle.Upgrade(uiPlan, snapshotID)
+			if err != nil {
+				lifecycle.Errorf("Failed to upgrade: %v", err)
+				os.Exit(1)
+			}
+		}
+		lifecycle.Titlef("Successfully upgraded, run \"cm selenoid rollback\" to restore snapshot %s", snapshotID)
+	},
+}
+
+var selenoidRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore configuration and versions saved by the last upgrade and restart",
+	Run: func(cmd *cobra.Command, args []string) {
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer lifecycle.Close()
+		uiLifecycle, err := createUILifecycle()
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		defer uiLifecycle.Close()
+
+		snapshotID, err := selenoid.RollbackLastUpgrade(lifecycle, uiLifecycle)
+		if err != nil {
+			lifecycle.Errorf("Failed to roll back: %v", err)
+			os.Exit(1)
+		}
+		lifecycle.Titlef("Successfully restored snapshot %s", snapshotID)
+	},
+}
+
+// createUILifecycle creates Selenoid UI lifecycle from Selenoid UI specific flags
+func createUILifecycle() (*selenoid.Lifecycle, error) {
+	selenoidVersion, selenoidArgs, selenoidEnv := version, args, env
+	defer func() {
+		version, args, env = selenoidVersion, selenoidArgs, selenoidEnv
+	}()
+	version, args, env = uiVersion, "", ""
+	return createLifecycle(uiConfigDir, uiPort)
+}
+
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const exitCodeDigestDrift = 5
+
+var selenoidVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that local browser images match digests pinned in browsers.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		if outputFormat != outputText {
+			quiet = true
+		}
+		lifecycle, err := createLifecycle(configDir, port)
+		if err != nil {
+			stderr("Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		statuses, err := lifecycle.VerifyDigests()
+		lifecycle.Close()
+		if err != nil {
+			stderr("Failed to verify images: %v\n", err)
+			os.Exit(1)
+		}
+		switch outputFormat {
+		case outputText:
+			lifecycle.PrintDigests(statuses)
+		case outputJSON:
+			data, _ := json.MarshalIndent(statuses, "", "    ")
+			fmt.Println(string(data))
+		case outputYAML:
+			data, _ := yaml.Marshal(statuses)
+			fmt.Print(string(data))
+		default:
+			stderr("Unsupported output format: %s\n", outputFormat)
+			os.Exit(1)
+		}
+		for _, s := range statuses {
+			if s.IsDrifted() {
+				os.Exit(exitCodeDigestDrift)
+			}
+		}
+	},
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aerokube/cm/selenoid"
+	"github.com/spf13/cobra"
+)
+
+var selenoidWriteLogCmd = &cobra.Command{
+	Use:    selenoid.LogWriterCommand + " <path>",
+	Short:  "Copy standard input to log file rotating it (started by detached processes)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := selenoid.WriteLog(args[0], os.Stdin)
+		if err != nil {
+			stderr("Failed to write log: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
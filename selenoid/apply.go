@@ -0,0 +1,189 @@
+package selenoid
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+type Action string
+
+const (
+	ActionDownload  Action = "download"
+	ActionConfigure Action = "configure"
+	ActionStart     Action = "start"
+	ActionRestart   Action = "restart"
+
+	appliedSelenoidSpecFileName = "selenoid.applied.json"
+	appliedUISpecFileName       = "selenoid-ui.applied.json"
+)
+
+type Change struct {
+	Action Action
+	Reason string
+}
+
+// Plan is a list of changes needed to bring the host to the state described in the manifest
+type Plan struct {
+	Service string
+	Changes []Change
+	ui      bool
+	spec    interface{}
+	path    string
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) has(action Action) bool {
+	for _, change := range p.Changes {
+		if change.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plan) add(action Action, reason string) {
+	p.Changes = append(p.Changes, Change{Action: action, Reason: reason})
+}
+
+func (l *Lifecycle) PlanSelenoid(spec *SelenoidSpec) *Plan {
+	plan := &Plan{
+		Service: "Selenoid",
+		spec:    spec,
+		path:    filepath.Join(spec.ConfigDir, appliedSelenoidSpecFileName),
+	}
+	var prev *SelenoidSpec
+	_ = loadAppliedSpec(plan.path, &prev)
+
+	if !l.downloadable.IsDownloaded() {
+		plan.add(ActionDownload, fmt.Sprintf("version %s is not downloaded", spec.Version))
+	} else if prev != nil && prev.Version != spec.Version {
+		plan.add(ActionDownload, fmt.Sprintf("version changed from %s to %s", prev.Version, spec.Version))
+	}
+
+	if !l.configurable.IsConfigured() {
+		plan.add(ActionConfigure, "not configured")
+	} else if prev == nil {
+		plan.add(ActionConfigure, "configuration was not created from a manifest")
+	} else if !reflect.DeepEqual(prev.configurationFields(), spec.configurationFields()) {
+		plan.add(ActionConfigure, "browsers configuration changed")
+	}
+
+	if !l.runnable.IsRunning() {
+		plan.add(ActionStart, "not running")
+	} else if !plan.Empty() {
+		plan.add(ActionRestart, "configuration changed")
+	} else if prev == nil || !reflect.DeepEqual(prev.runtimeFields(), spec.runtimeFields()) {
+		plan.add(ActionRestart, "startup settings changed")
+	}
+	return plan
+}
+
+func (l *Lifecycle) PlanUI(spec *UISpec) *Plan {
+	plan := &Plan{
+		Service: "Selenoid UI",
+		ui:      true,
+		spec:    spec,
+		path:    filepath.Join(spec.ConfigDir, appliedUISpecFileName),
+	}
+	var prev *UISpec
+	_ = loadAppliedSpec(plan.path, &prev)
+
+	if !l.downloadable.IsUIDownloaded() {
+		plan.add(ActionDownload, fmt.Sprintf("version %s is not downloaded", spec.Version))
+	} else if prev != nil && prev.Version != spec.Version {
+		plan.add(ActionDownload, fmt.Sprintf("version changed from %s to %s", prev.Version, spec.Version))
+	}
+
+	if !l.runnable.IsUIRunning() {
+		plan.add(ActionStart, "not running")
+	} else if !plan.Empty() {
+		plan.add(ActionRestart, "new version downloaded")
+	} else if prev == nil || !reflect.DeepEqual(*prev, *spec) {
+		plan.add(ActionRestart, "startup settings changed")
+	}
+	return plan
+}
+
+func (s *SelenoidSpec) configurationFields() []interface{} {
+	return []interface{}{s.UseDrivers, s.Registry, s.Browsers, s.BrowsersJson, s.Catalog, s.PinDigests, *s.LastVersions, s.BrowserEnv, s.ShmSize, s.Tmpfs}
+}
+
+func (s *SelenoidSpec) runtimeFields() []interface{} {
+	return []interface{}{s.Instance, s.ConfigDir, s.Port, s.Limit, s.Args, s.Env, s.Volumes, s.UserNS, s.DisableLogs}
+}
+
+func loadAppliedSpec(path string, spec interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, spec)
+}
+
+func (l *Lifecycle) Apply(plan *Plan) error {
+	for _, change := range plan.Changes {
+		l.Titlef("%s %s (%s)...", title.String(string(change.Action)), plan.Service, change.Reason)
+		err := l.applyChange(change.Action, plan.ui)
+		if err != nil {
+			return fmt.Errorf("failed to %s %s: %v", change.Action, plan.Service, err)
+		}
+	}
+	if plan.has(ActionStart) || plan.has(ActionRestart) {
+		var err error
+		if plan.ui {
+			err = l.waitUntilReady(plan.Service, selenoidUIReadinessUrls(l.readinessHost(), l.Config.Port), l.logsProvider.UILogs)
+		} else {
+			err = l.waitUntilReady(plan.Service, selenoidReadinessUrls(l.readinessHost(), l.Config.Port), l.logsProvider.Logs)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(plan.spec, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %v", err)
+	}
+	err = os.MkdirAll(filepath.Dir(plan.path), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	return os.WriteFile(plan.path, data, 0644)
+}
+
+func (l *Lifecycle) applyChange(action Action, ui bool) error {
+	switch action {
+	case ActionDownload:
+		if ui {
+			_, err := l.downloadable.DownloadUI()
+			return err
+		}
+		_, err := l.downloadable.Download()
+		return err
+	case ActionConfigure:
+		_, err := l.configurable.Configure()
+		return err
+	case ActionRestart:
+		if ui {
+			if err := l.runnable.StopUI(); err != nil {
+				return err
+			}
+			return l.runnable.StartUI()
+		}
+		if err := l.runnable.Stop(); err != nil {
+			return err
+		}
+		return l.runnable.Start()
+	case ActionStart:
+		if ui {
+			return l.runnable.StartUI()
+		}
+		return l.runnable.Start()
+	}
+	return fmt.Errorf("unknown action: %s", action)
+}
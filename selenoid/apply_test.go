@@ -0,0 +1,77 @@
+package selenoid
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func createPlanTestLifecycle(strategy *MockStrategy) *Lifecycle {
+	return &Lifecycle{
+		Config:       &LifecycleConfig{},
+		argsAware:    strategy,
+		statusAware:  strategy,
+		downloadable: strategy,
+		configurable: strategy,
+		runnable:     strategy,
+		logsProvider: strategy,
+		closer:       strategy,
+	}
+}
+
+func testSelenoidSpec(dir string) *SelenoidSpec {
+	spec := &SelenoidSpec{ConfigDir: dir}
+	spec.setDefaults()
+	return spec
+}
+
+func TestPlanAndApply(t *testing.T) {
+	withTmpDir(t, "test-plan", func(t *testing.T, dir string) {
+		strategy := &MockStrategy{}
+		lc := createPlanTestLifecycle(strategy)
+		spec := testSelenoidSpec(dir)
+
+		plan := lc.PlanSelenoid(spec)
+		assert.True(t, plan.has(ActionDownload))
+		assert.True(t, plan.has(ActionConfigure))
+		assert.True(t, plan.has(ActionStart))
+		assert.False(t, plan.has(ActionRestart))
+		assert.NoError(t, lc.Apply(plan))
+
+		strategy.isDownloaded = true
+		strategy.isRunning = true
+		strategy.isConfigured = true
+		assert.True(t, lc.PlanSelenoid(testSelenoidSpec(dir)).Empty())
+
+		changedSpec := testSelenoidSpec(dir)
+		changedSpec.Port = 4445
+		plan = lc.PlanSelenoid(changedSpec)
+		assert.Equal(t, []Change{{Action: ActionRestart, Reason: "startup settings changed"}}, plan.Changes)
+
+		changedSpec = testSelenoidSpec(dir)
+		changedSpec.Browsers = map[string][]string{"firefox": {">=120.0"}}
+		plan = lc.PlanSelenoid(changedSpec)
+		assert.True(t, plan.has(ActionConfigure))
+		assert.True(t, plan.has(ActionRestart))
+		assert.False(t, plan.has(ActionDownload))
+	})
+}
+
+func TestPlanUI(t *testing.T) {
+	withTmpDir(t, "test-plan", func(t *testing.T, dir string) {
+		strategy := &MockStrategy{isDownloaded: true, isRunning: true}
+		lc := createPlanTestLifecycle(strategy)
+		spec := &UISpec{ConfigDir: dir}
+		spec.setDefaults()
+
+		plan := lc.PlanUI(spec)
+		assert.Equal(t, []Change{{Action: ActionRestart, Reason: "startup settings changed"}}, plan.Changes)
+		assert.NoError(t, lc.Apply(plan))
+		assert.True(t, lc.PlanUI(spec).Empty())
+
+		spec.Version = "1.10.0"
+		plan = lc.PlanUI(spec)
+		assert.True(t, plan.has(ActionDownload))
+		assert.True(t, plan.has(ActionRestart))
+	})
+}
@@ -2,10 +2,12 @@ package selenoid
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
 	"time"
 
 	"github.com/fatih/color"
@@ -13,8 +15,8 @@ import (
 )
 
 type StatusProvider interface {
-	Status()
-	UIStatus()
+	Status() *Status
+	UIStatus() *Status
 }
 
 type ArgsProvider interface {
@@ -43,6 +45,71 @@ type Runnable interface {
 	StopUI() error
 }
 
+type LogsProvider interface {
+	Logs(w io.Writer, opts LogsOptions) error
+	UILogs(w io.Writer, opts LogsOptions) error
+}
+
+type LogsOptions struct {
+	Tail       int
+	Follow     bool
+	Since      time.Time
+	Timestamps bool
+}
+
+type SystemdUnitProvider interface {
+	SystemdUnit() (*SystemdUnit, error)
+	UISystemdUnit() (*SystemdUnit, error)
+}
+
+type Exporter interface {
+	Export(w io.Writer, opts ExportOptions) error
+}
+
+type ImageArchiver interface {
+	SaveImages(w io.Writer, refs []string) error
+	LoadImages(r io.Reader) error
+	RepinImages(cfg *SelenoidConfig) bool
+	VideoRecorderImage() string
+}
+
+type DigestVerifier interface {
+	VerifyDigests() ([]ImageDigestStatus, error)
+}
+
+type Upgradable interface {
+	InstalledVersion() string
+	InstalledUIVersion() string
+	LatestVersion() (string, error)
+	LatestUIVersion() (string, error)
+	AvailableBrowsers() (map[string][]string, error)
+}
+
+type ImagePruner interface {
+	Prune(opts PruneOptions) (*PruneReport, error)
+}
+
+type Cleaner interface {
+	Cleanup(opts CleanupOptions) (*CleanupReport, error)
+}
+
+type BrowsersLister interface {
+	ListBrowsers(opts BrowsersListOptions) ([]BrowserListing, error)
+}
+
+type BrowsersEditor interface {
+	AddBrowsers(opts BrowsersEditOptions) error
+	RemoveBrowsers(opts BrowsersEditOptions) error
+}
+
+type Reloadable interface {
+	Reload() error
+}
+
+type Supervisor interface {
+	Supervise(stop <-chan struct{}) error
+}
+
 type Logger struct {
 	Quiet bool
 }
@@ -113,11 +180,11 @@ type ArgsAware struct {
 }
 
 type EnvAware struct {
-	Env string
+	Env []string
 }
 
 type BrowserEnvAware struct {
-	BrowserEnv string
+	BrowserEnv []string
 }
 
 type PortAware struct {
@@ -132,6 +199,22 @@ type LogsAware struct {
 	DisableLogs bool
 }
 
+type InstanceAware struct {
+	Instance string
+}
+
+type RetryAware struct {
+	Retry RetryPolicy
+}
+
+// instanceName adds instance name suffix to container, network and process file names
+func (i *InstanceAware) instanceName(name string) string {
+	if i.Instance == "" {
+		return name
+	}
+	return name + "-" + i.Instance
+}
+
 const (
 	DefaultPort           = 4444
 	UIDefaultPort         = 8080
@@ -156,6 +239,8 @@ func joinPaths(baseDir string, elem []string) string {
 var (
 	selenoidConfigDirElem   = []string{".aerokube", "selenoid"}
 	selenoidUIConfigDirElem = []string{".aerokube", "selenoid-ui"}
+	instancesDirElem        = []string{".aerokube", "instances"}
+	instanceNameRegexp      = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
 )
 
 func GetSelenoidConfigDir() string {
@@ -165,3 +250,48 @@ func GetSelenoidConfigDir() string {
 func GetSelenoidUIConfigDir() string {
 	return joinPaths(getHomeDir(), selenoidUIConfigDirElem)
 }
+
+func GetSelenoidInstanceConfigDir(instance string) string {
+	return joinPaths(getHomeDir(), getInstanceConfigDirElem(instance, selenoidConfigDirElem))
+}
+
+func GetSelenoidUIInstanceConfigDir(instance string) string {
+	return joinPaths(getHomeDir(), getInstanceConfigDirElem(instance, selenoidUIConfigDirElem))
+}
+
+// getInstanceConfigDirElem returns ~/.aerokube/instances/<instance>/<service> for named instances
+func getInstanceConfigDirElem(instance string, defaultElem []string) []string {
+	if instance == "" {
+		return defaultElem
+	}
+	return withElem(instancesDirElem, instance, defaultElem[len(defaultElem)-1])
+}
+
+func withElem(elem []string, more ...string) []string {
+	return append(append([]string{}, elem...), more...)
+}
+
+func ValidateInstanceName(instance string) error {
+	if instance != "" && !instanceNameRegexp.MatchString(instance) {
+		return fmt.Errorf("invalid instance name %q: only letters, digits, \"_\", \".\" and \"-\" are allowed", instance)
+	}
+	return nil
+}
+
+// ListInstances returns names of all named instances having configuration directory
+func ListInstances() ([]string, error) {
+	entries, err := os.ReadDir(joinPaths(getHomeDir(), instancesDirElem))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ret = append(ret, entry.Name())
+		}
+	}
+	return ret, nil
+}
@@ -2,6 +2,7 @@ package selenoid
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	assert "github.com/stretchr/testify/require"
@@ -15,3 +16,25 @@ func TestGetConfigDir(t *testing.T) {
 	assert.NotEmpty(t, selenoidUIConfigDir)
 	assert.True(t, filepath.IsAbs(selenoidUIConfigDir))
 }
+
+func TestInstanceName(t *testing.T) {
+	assert.Equal(t, "selenoid", (&InstanceAware{}).instanceName("selenoid"))
+	assert.Equal(t, "selenoid-team", (&InstanceAware{Instance: "team"}).instanceName("selenoid"))
+}
+
+func TestGetInstanceConfigDir(t *testing.T) {
+	assert.Equal(t, GetSelenoidConfigDir(), GetSelenoidInstanceConfigDir(""))
+	assert.Equal(t, GetSelenoidUIConfigDir(), GetSelenoidUIInstanceConfigDir(""))
+	selenoidConfigDir := GetSelenoidInstanceConfigDir("team")
+	assert.True(t, strings.HasSuffix(selenoidConfigDir, filepath.Join("instances", "team", "selenoid")))
+	selenoidUIConfigDir := GetSelenoidUIInstanceConfigDir("team")
+	assert.True(t, strings.HasSuffix(selenoidUIConfigDir, filepath.Join("instances", "team", "selenoid-ui")))
+}
+
+func TestValidateInstanceName(t *testing.T) {
+	assert.NoError(t, ValidateInstanceName(""))
+	assert.NoError(t, ValidateInstanceName("team-1.branch_2"))
+	assert.Error(t, ValidateInstanceName("../team"))
+	assert.Error(t, ValidateInstanceName("-team"))
+	assert.Error(t, ValidateInstanceName("team name"))
+}
@@ -0,0 +1,144 @@
+package selenoid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/docker/api/types/image"
+	. "github.com/fvbommel/sortorder"
+)
+
+// maxTableVersions limits versions shown in one table cell, JSON and YAML output contain all versions
+const maxTableVersions = 5
+
+type BrowsersListOptions struct {
+	Remote bool
+}
+
+// BrowserListing shows catalog browser versions available in registry, pulled locally and used in browsers.json
+type BrowserListing struct {
+	Name  string `json:"name" yaml:"name"`
+	Image string `json:"image" yaml:"image"`
+	// Default is default version from browsers.json or the one configure would choose
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Selected are versions configure would process with current browsers and version flags
+	Selected   []string `json:"selected,omitempty" yaml:"selected,omitempty"`
+	Remote     []string `json:"remote,omitempty" yaml:"remote,omitempty"`
+	Local      []string `json:"local,omitempty" yaml:"local,omitempty"`
+	Configured []string `json:"configured,omitempty" yaml:"configured,omitempty"`
+}
+
+// ListBrowsers shows versions of requested browsers or of all catalog browsers without pulling images
+func (c *DockerConfigurator) ListBrowsers(opts BrowsersListOptions) ([]BrowserListing, error) {
+	catalog, err := c.loadCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load browsers catalog: %v", err)
+	}
+	cfg, err := c.currentConfig()
+	if err != nil {
+		return nil, err
+	}
+	images, err := c.docker.ImageList(context.Background(), image.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %v", err)
+	}
+	requestedBrowsers := parseRequestedBrowsers(&c.Logger, c.Browsers)
+	selected := c.getBrowsersToIterate(catalog, requestedBrowsers)
+	browsers := selected
+	if len(requestedBrowsers) == 0 {
+		browsers = catalog
+	}
+	var ret []BrowserListing
+	for _, name := range Catalog(browsers).Names() {
+		fullyQualifiedImage := c.getFullyQualifiedImageRef(browsers[name].Image)
+		bv := BrowserListing{
+			Name:  name,
+			Image: fullyQualifiedImage,
+		}
+		local := localImageTags(images, fullyQualifiedImage)
+		bv.Local = sortedTags(local, true)
+		if versions, ok := cfg[name]; ok {
+			bv.Default = versions.Default
+			for version := range versions.Versions {
+				bv.Configured = append(bv.Configured, version)
+			}
+			sort.Sort(sort.Reverse(Natural(bv.Configured)))
+		}
+		available := local
+		if opts.Remote {
+			available = c.fetchRawImageTags(browsers[name].Image)
+			bv.Remote = sortedTags(available, c.IncludePrereleases)
+		}
+		if _, ok := selected[name]; ok {
+			bv.Selected = c.filterTags(available, requestedBrowsers[name])
+		}
+		if bv.Default == "" && len(bv.Selected) > 0 {
+			bv.Default = bv.Selected[0]
+		}
+		ret = append(ret, bv)
+	}
+	return ret, nil
+}
+
+// currentConfig returns browsers.json contents or empty configuration when Selenoid was not configured yet
+func (c *DockerConfigurator) currentConfig() (SelenoidConfig, error) {
+	configPath := getSelenoidConfigPath(c.ConfigDir)
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return SelenoidConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read browsers.json: %v", err)
+	}
+	var cfg SelenoidConfig
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse browsers.json from %s: %v", configPath, err)
+	}
+	return cfg, nil
+}
+
+// localImageTags returns tags of pulled images from repository
+func localImageTags(images []image.Summary, ref string) []string {
+	repo := normalizedRepositoryName(ref)
+	var tags []string
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			if normalizedRepositoryName(tag) == repo {
+				tags = append(tags, imageTag(tag))
+			}
+		}
+	}
+	return uniqueStrings(tags)
+}
+
+func (l *Lifecycle) PrintBrowsers(browsers []BrowserListing) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "BROWSER\tDEFAULT\tSELECTED\tREMOTE\tLOCAL\tCONFIGURED")
+	for _, b := range browsers {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", b.Name, versionOrDash(b.Default), versionList(b.Selected), versionList(b.Remote), versionList(b.Local), versionList(b.Configured))
+	}
+	_ = w.Flush()
+	if len(browsers) == 0 {
+		l.Errorf("No browsers found in catalog")
+	}
+}
+
+func versionList(versions []string) string {
+	if len(versions) > maxTableVersions {
+		return fmt.Sprintf("%s (+%d more)", strings.Join(versions[:maxTableVersions], ", "), len(versions)-maxTableVersions)
+	}
+	return versionOrDash(strings.Join(versions, ", "))
+}
+
+func versionOrDash(version string) string {
+	if version == "" {
+		return "-"
+	}
+	return version
+}
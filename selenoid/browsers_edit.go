@@ -0,0 +1,238 @@
+package selenoid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	. "github.com/fvbommel/sortorder"
+)
+
+const (
+	defaultKey  = "default"
+	versionsKey = "versions"
+)
+
+type BrowsersEditOptions struct {
+	// Browsers are browser:version pairs, without version the newest release is added or all versions are removed
+	Browsers   []string
+	SetDefault bool
+}
+
+// editableConfig is browsers.json where only default version and versions list are parsed,
+// so that fields cm knows nothing about and hand edits of existing versions are written back as is
+type editableConfig map[string]editableBrowser
+
+type editableBrowser map[string]json.RawMessage
+
+func (b editableBrowser) versions() (map[string]json.RawMessage, error) {
+	ret := make(map[string]json.RawMessage)
+	if data, ok := b[versionsKey]; ok {
+		err := json.Unmarshal(data, &ret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (b editableBrowser) setVersions(versions map[string]json.RawMessage) error {
+	data, err := json.Marshal(versions)
+	if err != nil {
+		return err
+	}
+	b[versionsKey] = data
+	return nil
+}
+
+func (b editableBrowser) defaultVersion() string {
+	var ret string
+	_ = json.Unmarshal(b[defaultKey], &ret)
+	return ret
+}
+
+func (b editableBrowser) setDefaultVersion(version string) error {
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	b[defaultKey] = data
+	return nil
+}
+
+func parseBrowserSpec(spec string) (string, string) {
+	name, version, _ := strings.Cut(spec, colon)
+	return strings.TrimSpace(name), strings.TrimSpace(version)
+}
+
+func (c *DockerConfigurator) readEditableConfig() (editableConfig, error) {
+	configPath := getSelenoidConfigPath(c.ConfigDir)
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return editableConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read browsers.json: %v", err)
+	}
+	cfg := editableConfig{}
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse browsers.json from %s: %v", configPath, err)
+	}
+	return cfg, nil
+}
+
+func (c *DockerConfigurator) writeEditableConfig(cfg editableConfig) error {
+	err := c.createConfigDir()
+	if err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %v", err)
+	}
+	return os.WriteFile(getSelenoidConfigPath(c.ConfigDir), data, 0644)
+}
+
+type browserAddition struct {
+	name    string
+	version string
+	browser *CatalogBrowser
+	image   string
+}
+
+// AddBrowsers pulls images of requested browser versions and adds them to browsers.json
+func (c *DockerConfigurator) AddBrowsers(opts BrowsersEditOptions) error {
+	catalog, err := c.loadCatalog()
+	if err != nil {
+		return fmt.Errorf("failed to load browsers catalog: %v", err)
+	}
+	cfg, err := c.readEditableConfig()
+	if err != nil {
+		return err
+	}
+	var additions []browserAddition
+	var refs []string
+	for _, spec := range opts.Browsers {
+		name, version := parseBrowserSpec(spec)
+		browser, ok := catalog[name]
+		if !ok {
+			return fmt.Errorf("unsupported browser: %s", name)
+		}
+		if version == "" {
+			tags := c.filterTags(c.fetchRawImageTags(browser.Image), nil)
+			if len(tags) == 0 {
+				return fmt.Errorf("no versions of %s found in registry", name)
+			}
+			version = tags[0]
+		}
+		image := c.getFullyQualifiedImageRef(browser.Image)
+		additions = append(additions, browserAddition{name: name, version: version, browser: browser, image: image})
+		refs = append(refs, imageWithTag(image, version))
+	}
+	if c.DownloadNeeded {
+		c.Titlef("Pulling images...")
+		failed := c.pullImages(context.Background(), refs)
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to pull %d of %d images", len(failed), len(uniqueStrings(refs)))
+		}
+	}
+	for _, a := range additions {
+		entry, ok := cfg[a.name]
+		if !ok {
+			entry = editableBrowser{}
+			cfg[a.name] = entry
+		}
+		versions, err := entry.versions()
+		if err != nil {
+			return fmt.Errorf("invalid versions of %s in browsers.json: %v", a.name, err)
+		}
+		if _, ok := versions[a.version]; ok {
+			c.Pointf("Browser %s %s is already configured", color.GreenString(a.name), a.version)
+		} else {
+			created := c.createVersions(a.browser, a.image, []string{a.version})
+			if c.PinDigests {
+				c.pinDigests(created)
+			}
+			data, err := json.Marshal(created.Versions[a.version])
+			if err != nil {
+				return fmt.Errorf("failed to marshal json: %v", err)
+			}
+			versions[a.version] = data
+			err = entry.setVersions(versions)
+			if err != nil {
+				return fmt.Errorf("failed to marshal json: %v", err)
+			}
+			c.Pointf("Added %s %s", color.GreenString(a.name), color.BlueString(a.version))
+		}
+		if opts.SetDefault || entry.defaultVersion() == "" {
+			err = entry.setDefaultVersion(a.version)
+			if err != nil {
+				return fmt.Errorf("failed to marshal json: %v", err)
+			}
+		}
+	}
+	return c.writeEditableConfig(cfg)
+}
+
+// RemoveBrowsers removes browser versions from browsers.json, images are left to prune command
+func (c *DockerConfigurator) RemoveBrowsers(opts BrowsersEditOptions) error {
+	cfg, err := c.readEditableConfig()
+	if err != nil {
+		return err
+	}
+	for _, spec := range opts.Browsers {
+		name, version := parseBrowserSpec(spec)
+		entry, ok := cfg[name]
+		if !ok {
+			return fmt.Errorf("browser %s is not configured", name)
+		}
+		if version == "" {
+			delete(cfg, name)
+			c.Pointf("Removed all versions of %s", color.GreenString(name))
+			continue
+		}
+		versions, err := entry.versions()
+		if err != nil {
+			return fmt.Errorf("invalid versions of %s in browsers.json: %v", name, err)
+		}
+		if _, ok := versions[version]; !ok {
+			return fmt.Errorf("browser %s %s is not configured", name, version)
+		}
+		delete(versions, version)
+		c.Pointf("Removed %s %s", color.GreenString(name), color.BlueString(version))
+		if len(versions) == 0 {
+			delete(cfg, name)
+			continue
+		}
+		err = entry.setVersions(versions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal json: %v", err)
+		}
+		if entry.defaultVersion() == version {
+			newDefault := newestVersion(versions)
+			err = entry.setDefaultVersion(newDefault)
+			if err != nil {
+				return fmt.Errorf("failed to marshal json: %v", err)
+			}
+			c.Pointf("Default version of %s is now %s", color.GreenString(name), color.BlueString(newDefault))
+		}
+	}
+	return c.writeEditableConfig(cfg)
+}
+
+func newestVersion(versions map[string]json.RawMessage) string {
+	var ret []string
+	for version := range versions {
+		ret = append(ret, version)
+	}
+	if sorted := sortedTags(ret, true); len(sorted) > 0 {
+		return sorted[0]
+	}
+	sort.Sort(sort.Reverse(Natural(ret)))
+	return ret[0]
+}
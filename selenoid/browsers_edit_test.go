@@ -0,0 +1,112 @@
+package selenoid
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+const handEditedConfig = `{
+	"firefox": {
+		"default": "46.0",
+		"versions": {
+			"46.0": {"image": "selenoid/firefox:46.0", "port": "4444", "hosts": ["example.com:127.0.0.1"], "custom": {"key": "value"}},
+			"45.0": {"image": "selenoid/firefox:45.0", "port": "4444"}
+		}
+	},
+	"opera": {"default": "44.0", "versions": {"44.0": {"image": "selenoid/opera:44.0"}}}
+}`
+
+func readRawConfig(t *testing.T, dir string) map[string]map[string]interface{} {
+	data, err := os.ReadFile(getSelenoidConfigPath(dir))
+	assert.NoError(t, err)
+	var ret map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &ret))
+	return ret
+}
+
+func TestAddBrowsers(t *testing.T) {
+	withTmpDir(t, "test-browsers-add", func(t *testing.T, dir string) {
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(handEditedConfig), 0644))
+		c, err := NewDockerConfigurator(&LifecycleConfig{
+			ConfigDir:   dir,
+			Quiet:       true,
+			Download:    true,
+			RegistryUrl: mockDockerServer.URL,
+			BrowserEnv:  []string{"LANG=en_US.UTF-8"},
+		})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		assert.NoError(t, c.AddBrowsers(BrowsersEditOptions{Browsers: []string{"firefox:7.0", "firefox:46.0", "chrome:120.0"}}))
+		cfg := readRawConfig(t, dir)
+		assert.Equal(t, "46.0", cfg[firefox]["default"])
+		versions := cfg[firefox]["versions"].(map[string]interface{})
+		assert.Len(t, versions, 3)
+		assert.Equal(t, map[string]interface{}{
+			"image":  "selenoid/firefox:46.0",
+			"port":   "4444",
+			"hosts":  []interface{}{"example.com:127.0.0.1"},
+			"custom": map[string]interface{}{"key": "value"},
+		}, versions["46.0"])
+		added := versions["7.0"].(map[string]interface{})
+		assert.Equal(t, c.getFullyQualifiedImageRef("selenoid/firefox:7.0"), added["image"])
+		assert.Equal(t, "/wd/hub", added["path"])
+		assert.Equal(t, []interface{}{"LANG=en_US.UTF-8"}, added["env"])
+		assert.Equal(t, "120.0", cfg["chrome"]["default"])
+		assert.Equal(t, map[string]interface{}{"image": "selenoid/opera:44.0"}, cfg[opera]["versions"].(map[string]interface{})["44.0"])
+
+		assert.NoError(t, c.AddBrowsers(BrowsersEditOptions{Browsers: []string{"firefox"}, SetDefault: true}))
+		assert.Equal(t, "46.0", readRawConfig(t, dir)[firefox]["default"])
+		assert.NoError(t, c.AddBrowsers(BrowsersEditOptions{Browsers: []string{"firefox:45.0"}, SetDefault: true}))
+		assert.Equal(t, "45.0", readRawConfig(t, dir)[firefox]["default"])
+
+		assert.Error(t, c.AddBrowsers(BrowsersEditOptions{Browsers: []string{"unknown:1.0"}}))
+	})
+}
+
+func TestAddBrowsersPullFailure(t *testing.T) {
+	withTmpDir(t, "test-browsers-add", func(t *testing.T, dir string) {
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(handEditedConfig), 0644))
+		catalogPath := filepath.Join(dir, "catalog.yml")
+		assert.NoError(t, os.WriteFile(catalogPath, []byte("firefox:\n  image: selenoid/firefox\nmissing:\n  image: selenoid/missing\n"), 0644))
+		c, err := NewDockerConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true, Download: true, Catalog: catalogPath})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		assert.Error(t, c.AddBrowsers(BrowsersEditOptions{Browsers: []string{"firefox:7.0", "missing:1.0"}}))
+		data, err := os.ReadFile(getSelenoidConfigPath(dir))
+		assert.NoError(t, err)
+		assert.Equal(t, handEditedConfig, string(data))
+	})
+}
+
+func TestRemoveBrowsers(t *testing.T) {
+	withTmpDir(t, "test-browsers-remove", func(t *testing.T, dir string) {
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(handEditedConfig), 0644))
+		c, err := NewDockerConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		assert.NoError(t, c.RemoveBrowsers(BrowsersEditOptions{Browsers: []string{"firefox:46.0", "opera"}}))
+		cfg := readRawConfig(t, dir)
+		assert.NotContains(t, cfg, opera)
+		assert.Equal(t, "45.0", cfg[firefox]["default"])
+		assert.Equal(t, map[string]interface{}{
+			"45.0": map[string]interface{}{"image": "selenoid/firefox:45.0", "port": "4444"},
+		}, cfg[firefox]["versions"])
+
+		assert.Error(t, c.RemoveBrowsers(BrowsersEditOptions{Browsers: []string{"firefox:46.0"}}))
+		assert.Error(t, c.RemoveBrowsers(BrowsersEditOptions{Browsers: []string{"chrome"}}))
+		assert.NoError(t, c.RemoveBrowsers(BrowsersEditOptions{Browsers: []string{"firefox:45.0"}}))
+		assert.Empty(t, readRawConfig(t, dir))
+	})
+}
+
+func TestNewestVersion(t *testing.T) {
+	assert.Equal(t, "10.0", newestVersion(map[string]json.RawMessage{"9.0": nil, "10.0": nil, "10.0-beta": nil}))
+	assert.Equal(t, "custom_2", newestVersion(map[string]json.RawMessage{"custom_1": nil, "custom_2": nil}))
+}
@@ -0,0 +1,84 @@
+package selenoid
+
+import (
+	"os"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestListBrowsers(t *testing.T) {
+	srv := mockPruneDockerServer(&requestRecorder{})
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	withTmpDir(t, "test-browsers-list", func(t *testing.T, dir string) {
+		data := `{"firefox": {"default": "46.0", "versions": {"45.0": {"image": "selenoid/firefox:45.0"}, "46.0": {"image": "selenoid/firefox:46.0"}}}}`
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(data), 0644))
+
+		c, err := NewDockerConfigurator(&LifecycleConfig{
+			ConfigDir:       dir,
+			Quiet:           true,
+			Browsers:        "firefox:>=45.0;opera",
+			LastVersions:    2,
+			RegistryUrl:     DefaultRegistryUrl,
+			RegistryMirrors: []string{srv.URL},
+		})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		browsers, err := c.ListBrowsers(BrowsersListOptions{Remote: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []BrowserListing{
+			{
+				Name:       "firefox",
+				Image:      "selenoid/firefox",
+				Default:    "46.0",
+				Selected:   []string{"46.0", "45.0"},
+				Remote:     []string{"46.0", "45.0", "7.0"},
+				Local:      []string{"46.0", "45.0", "44.0"},
+				Configured: []string{"46.0", "45.0"},
+			},
+			{
+				Name:     "opera",
+				Image:    "selenoid/opera",
+				Default:  "44.0",
+				Selected: []string{"44.0"},
+				Remote:   []string{"44.0"},
+				Local:    []string{"33.0", "32.0", "31.0"},
+			},
+		}, browsers)
+	})
+}
+
+func TestListAllCatalogBrowsers(t *testing.T) {
+	srv := mockPruneDockerServer(&requestRecorder{})
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	withTmpDir(t, "test-browsers-list", func(t *testing.T, dir string) {
+		c, err := NewDockerConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true, LastVersions: 1})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		browsers, err := c.ListBrowsers(BrowsersListOptions{})
+		assert.NoError(t, err)
+		var names []string
+		for _, b := range browsers {
+			assert.Empty(t, b.Remote)
+			assert.Empty(t, b.Configured)
+			names = append(names, b.Name)
+		}
+		assert.Equal(t, []string{edge, android, "chrome", firefox, opera}, names)
+		assert.Empty(t, browsers[1].Selected)
+		assert.Equal(t, BrowserListing{Name: "chrome", Image: "selenoid/chrome", Default: "120.0", Selected: []string{"120.0"}, Local: []string{"120.0"}}, browsers[2])
+		assert.Equal(t, []string{"46.0"}, browsers[3].Selected)
+		assert.Equal(t, []string{"46.0", "45.0", "44.0"}, browsers[3].Local)
+	})
+}
+
+func TestListBrowsersInDriversMode(t *testing.T) {
+	lc := &Lifecycle{}
+	_, err := lc.ListBrowsers(BrowsersListOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,398 @@
+package selenoid
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	bundleMetadataFile = "bundle.json"
+	bundleBrowsersFile = "browsers.json"
+	bundleImagesFile   = "images.tar"
+	bundleFilesDir     = "files"
+	bundleSelenoidDir  = "selenoid"
+	bundleUIDir        = "selenoid-ui"
+	bundleCacheDir     = "cache"
+)
+
+type BundleOptions struct {
+	UI          bool
+	UIVersion   string
+	UIConfigDir string
+}
+
+// bundleMetadata is stored as the first bundle entry
+type bundleMetadata struct {
+	Mode      string    `json:"mode"`
+	Created   time.Time `json:"created"`
+	ConfigDir string    `json:"configDir"`
+	UI        bool      `json:"ui,omitempty"`
+	Images    []string  `json:"images,omitempty"`
+}
+
+// CreateBundle downloads and configures everything into a temporary directory and writes it as tar.gz archive
+func (l *Lifecycle) CreateBundle(w io.Writer, opts BundleOptions) error {
+	stagingDir, err := os.MkdirTemp("", "cm-bundle")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	cfg := *l.Config
+	cfg.ConfigDir = filepath.Join(stagingDir, bundleSelenoidDir)
+	cfg.CacheDir = filepath.Join(stagingDir, bundleCacheDir)
+	cfg.Download = true
+	cfg.Adopt = false
+	staging, err := NewLifecycle(&cfg)
+	if err != nil {
+		return err
+	}
+	defer staging.Close()
+
+	metadata := &bundleMetadata{
+		Mode:      ModeDocker,
+		Created:   time.Now(),
+		ConfigDir: cfg.ConfigDir,
+		UI:        opts.UI,
+	}
+	if cfg.UseDrivers {
+		metadata.Mode = ModeDrivers
+	}
+
+	l.Titlef("Downloading Selenoid...")
+	ref, err := staging.downloadable.Download()
+	if err != nil {
+		return fmt.Errorf("failed to download Selenoid: %v", err)
+	}
+	metadata.Images = append(metadata.Images, ref)
+	l.Titlef("Configuring Selenoid...")
+	selenoidConfig, err := staging.configurable.Configure()
+	if err != nil {
+		return fmt.Errorf("failed to configure Selenoid: %v", err)
+	}
+	if opts.UI {
+		uiCfg := cfg
+		uiCfg.ConfigDir = filepath.Join(stagingDir, bundleUIDir)
+		uiCfg.Version = opts.UIVersion
+		uiStaging, err := NewLifecycle(&uiCfg)
+		if err != nil {
+			return err
+		}
+		defer uiStaging.Close()
+		l.Titlef("Downloading Selenoid UI...")
+		uiRef, err := uiStaging.downloadable.DownloadUI()
+		if err != nil {
+			return fmt.Errorf("failed to download Selenoid UI: %v", err)
+		}
+		metadata.Images = append(metadata.Images, uiRef)
+	}
+
+	if cfg.UseDrivers {
+		metadata.Images = nil
+	} else {
+		metadata.Images = append(metadata.Images, bundleImages(selenoidConfig)...)
+		metadata.Images = uniqueStrings(append(metadata.Images, staging.archiver.VideoRecorderImage()))
+	}
+
+	l.Titlef("Writing bundle...")
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	err = writeBundle(tw, stagingDir, metadata, staging.archiver)
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %v", err)
+	}
+	err = tw.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %v", err)
+	}
+	return gzw.Close()
+}
+
+func browserImages(cfg *SelenoidConfig) []string {
+	var ret []string
+	for _, versions := range *cfg {
+		for _, browser := range versions.Versions {
+			if ref, ok := browser.Image.(string); ok {
+				ret = append(ret, ref)
+			}
+		}
+	}
+	return ret
+}
+
+// bundleImages also returns tagged references for images pinned to digests: loaded images have no repository
+// digests, so on target host they can only be found by tag
+func bundleImages(cfg *SelenoidConfig) []string {
+	var ret []string
+	for _, versions := range *cfg {
+		for version, browser := range versions.Versions {
+			if ref, ok := browser.Image.(string); ok {
+				ret = append(ret, ref)
+				if strings.Contains(ref, digestSeparator) {
+					ret = append(ret, imageWithTag(repositoryName(ref), version))
+				}
+			}
+		}
+	}
+	return ret
+}
+
+func writeBundle(tw *tar.Writer, stagingDir string, metadata *bundleMetadata, archiver ImageArchiver) error {
+	data, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return err
+	}
+	err = addBundleData(tw, bundleMetadataFile, data)
+	if err != nil {
+		return err
+	}
+	err = addBundleFile(tw, bundleBrowsersFile, getSelenoidConfigPath(metadata.ConfigDir))
+	if err != nil {
+		return err
+	}
+	if metadata.Mode == ModeDocker {
+		imagesPath := filepath.Join(stagingDir, bundleImagesFile)
+		err = saveImages(archiver, imagesPath, metadata.Images)
+		if err != nil {
+			return err
+		}
+		return addBundleFile(tw, bundleImagesFile, imagesPath)
+	}
+	for _, dir := range []string{bundleSelenoidDir, bundleUIDir, bundleCacheDir} {
+		err = addBundleDir(tw, path.Join(bundleFilesDir, dir), filepath.Join(stagingDir, dir))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveImages(archiver ImageArchiver, outputPath string, refs []string) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return archiver.SaveImages(f, refs)
+}
+
+func addBundleData(tw *tar.Writer, name string, data []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+func addBundleFile(tw *tar.Writer, name string, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	err = tw.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// addBundleDir adds all regular files from directory except browsers.json
+func addBundleDir(tw *tar.Writer, prefix string, dir string) error {
+	if !fileExists(dir) {
+		return nil
+	}
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == bundleBrowsersFile {
+			return nil
+		}
+		return addBundleFile(tw, path.Join(prefix, filepath.ToSlash(rel)), p)
+	})
+}
+
+// InstallBundle loads images and extracts files from bundle created with CreateBundle without network access
+func (l *Lifecycle) InstallBundle(r io.Reader, opts BundleOptions) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %v", err)
+	}
+	defer gzr.Close()
+	tr := tar.NewReader(gzr)
+	var metadata *bundleMetadata
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read bundle: %v", err)
+		}
+		if header.Name == bundleMetadataFile {
+			metadata, err = l.readBundleMetadata(tr)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if metadata == nil {
+			return errors.New("invalid bundle: metadata is missing")
+		}
+		err = l.installBundleEntry(tr, header, metadata, opts)
+		if err != nil {
+			return fmt.Errorf("failed to install %s: %v", header.Name, err)
+		}
+	}
+	if metadata == nil {
+		return errors.New("invalid bundle: metadata is missing")
+	}
+	if metadata.Mode == ModeDocker && l.archiver != nil {
+		err = l.repinImages()
+		if err != nil {
+			return fmt.Errorf("failed to update browsers.json: %v", err)
+		}
+	}
+	l.Titlef("Bundle installed to %v", color.GreenString(l.Config.ConfigDir))
+	return nil
+}
+
+// repinImages refers to loaded images by tag when pinned digests can not be found locally
+func (l *Lifecycle) repinImages() error {
+	configPath := getSelenoidConfigPath(l.Config.ConfigDir)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	var cfg SelenoidConfig
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return fmt.Errorf("invalid browsers.json: %v", err)
+	}
+	if !l.archiver.RepinImages(&cfg) {
+		return nil
+	}
+	data, err = json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, data, 0644)
+}
+
+func (l *Lifecycle) readBundleMetadata(r io.Reader) (*bundleMetadata, error) {
+	var metadata bundleMetadata
+	err := json.NewDecoder(r).Decode(&metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle metadata: %v", err)
+	}
+	mode := ModeDocker
+	if l.Config.UseDrivers {
+		mode = ModeDrivers
+	}
+	if metadata.Mode != mode {
+		return nil, fmt.Errorf("bundle was created in %s mode but %s mode is used", metadata.Mode, mode)
+	}
+	l.Titlef("Installing bundle created at %v...", metadata.Created.Format(time.RFC3339))
+	return &metadata, nil
+}
+
+func (l *Lifecycle) installBundleEntry(r io.Reader, header *tar.Header, metadata *bundleMetadata, opts BundleOptions) error {
+	switch header.Name {
+	case bundleImagesFile:
+		if l.archiver == nil {
+			return errors.New("images can only be loaded in Docker mode")
+		}
+		l.Titlef("Loading images...")
+		return l.archiver.LoadImages(r)
+	case bundleBrowsersFile:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		data, err = relocateConfig(data, metadata.ConfigDir, l.Config.ConfigDir)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(l.Config.ConfigDir, os.ModePerm)
+		if err != nil {
+			return err
+		}
+		l.Pointf("Saving configuration to %v", color.BlueString(getSelenoidConfigPath(l.Config.ConfigDir)))
+		return os.WriteFile(getSelenoidConfigPath(l.Config.ConfigDir), data, 0644)
+	}
+	dirs := map[string]string{
+		bundleSelenoidDir: l.Config.ConfigDir,
+		bundleUIDir:       opts.UIConfigDir,
+		bundleCacheDir:    cacheDirOrDefault(l.Config.CacheDir, l.Config.ConfigDir),
+	}
+	for dir, outputDir := range dirs {
+		prefix := path.Join(bundleFilesDir, dir) + "/"
+		if !strings.HasPrefix(header.Name, prefix) {
+			continue
+		}
+		rel := path.Clean(strings.TrimPrefix(header.Name, prefix))
+		if rel == ".." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) || outputDir == "" {
+			return errors.New("unexpected file location")
+		}
+		outputPath := filepath.Join(outputDir, filepath.FromSlash(rel))
+		l.Pointf("Extracting %v", color.BlueString(outputPath))
+		return outputFile(outputPath, header.FileInfo().Mode(), r)
+	}
+	l.Pointf("Skipping unknown bundle entry %s", header.Name)
+	return nil
+}
+
+// relocateConfig replaces bundle configuration directory in driver commands with the actual one
+func relocateConfig(data []byte, fromDir string, toDir string) ([]byte, error) {
+	var cfg SelenoidConfig
+	err := json.Unmarshal(data, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid browsers.json: %v", err)
+	}
+	for _, versions := range cfg {
+		for _, browser := range versions.Versions {
+			command, ok := browser.Image.([]interface{})
+			if !ok {
+				continue
+			}
+			for i, piece := range command {
+				if s, ok := piece.(string); ok && strings.HasPrefix(s, fromDir) {
+					command[i] = toDir + strings.TrimPrefix(s, fromDir)
+				}
+			}
+		}
+	}
+	return json.MarshalIndent(cfg, "", "    ")
+}
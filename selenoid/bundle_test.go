@@ -0,0 +1,205 @@
+package selenoid
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestDriversBundle(t *testing.T) {
+	withTmpDir(t, "test-bundle", func(t *testing.T, dir string) {
+		lc, err := NewLifecycle(&LifecycleConfig{
+			UseDrivers:     true,
+			Quiet:          true,
+			ConfigDir:      filepath.Join(dir, "source"),
+			Browsers:       "first;second;safari",
+			DriversInfoUrl: mockServerUrl(mockDriverServer, "/browsers.json"),
+			GithubBaseUrl:  mockDriverServer.URL + "/",
+			OS:             runtime.GOOS,
+			Arch:           runtime.GOARCH,
+			Version:        Latest,
+		})
+		assert.NoError(t, err)
+		var bundle bytes.Buffer
+		assert.NoError(t, lc.CreateBundle(&bundle, BundleOptions{UI: true, UIVersion: previousReleaseTag}))
+		assert.False(t, fileExists(lc.Config.ConfigDir))
+
+		targetDir := filepath.Join(dir, "target")
+		uiDir := filepath.Join(dir, "target-ui")
+		target, err := NewLifecycle(&LifecycleConfig{
+			UseDrivers: true,
+			Quiet:      true,
+			ConfigDir:  targetDir,
+			CacheDir:   filepath.Join(dir, "cache"),
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, target.InstallBundle(&bundle, BundleOptions{UIConfigDir: uiDir}))
+
+		checkContentsEqual(t, filepath.Join(targetDir, getSelenoidReleaseFileName()), latestReleaseTag)
+		checkContentsEqual(t, filepath.Join(uiDir, getSelenoidUIReleaseFileName()), previousReleaseTag)
+		assert.True(t, fileExists(filepath.Join(targetDir, "zip-testfile")))
+		assert.True(t, fileExists(filepath.Join(targetDir, "gzip-testfile")))
+
+		var cfg SelenoidConfig
+		assert.NoError(t, json.Unmarshal(readFile(t, getSelenoidConfigPath(targetDir)), &cfg))
+		assert.Len(t, cfg, 3)
+		assert.Equal(t, []interface{}{filepath.Join(targetDir, "zip-testfile")}, cfg["first"].Versions[Latest].Image)
+		assert.Equal(t, []interface{}{"/usr/bin/safaridriver"}, cfg["safari"].Versions[Latest].Image)
+	})
+}
+
+func TestDockerBundle(t *testing.T) {
+	srv := mockDockerServerWith(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == "/_ping" {
+			w.WriteHeader(http.StatusOK)
+			return true
+		}
+		return false
+	})
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	withTmpDir(t, "test-bundle", func(t *testing.T, dir string) {
+		lc, err := NewLifecycle(&LifecycleConfig{
+			Quiet:        true,
+			ConfigDir:    dir,
+			RegistryUrl:  srv.URL,
+			Browsers:     "firefox",
+			LastVersions: 1,
+			Version:      Latest,
+		})
+		assert.NoError(t, err)
+		defer lc.Close()
+		var bundle bytes.Buffer
+		assert.NoError(t, lc.CreateBundle(&bundle, BundleOptions{}))
+		assert.Equal(t, []string{bundleMetadataFile, bundleBrowsersFile, bundleImagesFile}, bundleEntries(t, bundle.Bytes()))
+
+		assert.NoError(t, lc.InstallBundle(bytes.NewReader(bundle.Bytes()), BundleOptions{}))
+		var cfg SelenoidConfig
+		assert.NoError(t, json.Unmarshal(readFile(t, getSelenoidConfigPath(dir)), &cfg))
+		assert.Contains(t, cfg, "firefox")
+
+		drivers, err := NewLifecycle(&LifecycleConfig{UseDrivers: true, Quiet: true, ConfigDir: dir})
+		assert.NoError(t, err)
+		err = drivers.InstallBundle(bytes.NewReader(bundle.Bytes()), BundleOptions{})
+		assert.EqualError(t, err, "bundle was created in docker mode but drivers mode is used")
+	})
+}
+
+func TestDockerBundleWithPinnedDigests(t *testing.T) {
+	var loaded atomic.Bool
+	srv := mockDockerServerWith(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == "/_ping" {
+			w.WriteHeader(http.StatusOK)
+			return true
+		}
+		if !loaded.Load() || !strings.HasPrefix(r.URL.Path, "/v1.29/images/") || !strings.HasSuffix(r.URL.Path, "/json") {
+			return false
+		}
+		// Loaded images have no repository digests
+		if strings.Contains(r.URL.Path, digestSeparator) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "No such image"}`))
+			return true
+		}
+		_, _ = w.Write([]byte(`{"Id": "sha256:e90e34656806", "RepoDigests": []}`))
+		return true
+	})
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	withTmpDir(t, "test-bundle", func(t *testing.T, dir string) {
+		lc, err := NewLifecycle(&LifecycleConfig{
+			Quiet:        true,
+			ConfigDir:    dir,
+			RegistryUrl:  srv.URL,
+			Browsers:     "firefox",
+			LastVersions: 1,
+			PinDigests:   true,
+			Version:      Latest,
+		})
+		assert.NoError(t, err)
+		defer lc.Close()
+		var bundle bytes.Buffer
+		assert.NoError(t, lc.CreateBundle(&bundle, BundleOptions{}))
+		firefox := lc.archiver.(*DockerConfigurator).getFullyQualifiedImageRef("selenoid/firefox")
+		pinned := firefox + "@" + testDigest(firefox)
+		images := bundleImagesData(t, bundle.Bytes())
+		assert.Contains(t, images, pinned)
+		assert.Contains(t, images, firefox+":46.0")
+
+		assert.NoError(t, lc.InstallBundle(bytes.NewReader(bundle.Bytes()), BundleOptions{}))
+		var cfg SelenoidConfig
+		assert.NoError(t, json.Unmarshal(readFile(t, getSelenoidConfigPath(dir)), &cfg))
+		assert.Equal(t, pinned, cfg["firefox"].Versions["46.0"].Image)
+
+		loaded.Store(true)
+		assert.NoError(t, lc.InstallBundle(bytes.NewReader(bundle.Bytes()), BundleOptions{}))
+		assert.NoError(t, json.Unmarshal(readFile(t, getSelenoidConfigPath(dir)), &cfg))
+		assert.Equal(t, firefox+":46.0", cfg["firefox"].Versions["46.0"].Image)
+	})
+}
+
+func bundleImagesData(t *testing.T, data []byte) string {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		assert.NoError(t, err)
+		if header.Name == bundleImagesFile {
+			images, err := io.ReadAll(tr)
+			assert.NoError(t, err)
+			return string(images)
+		}
+	}
+}
+
+func bundleEntries(t *testing.T, data []byte) []string {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gzr)
+	var ret []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		ret = append(ret, header.Name)
+	}
+	return ret
+}
+
+func TestInstallBundleWithoutMetadata(t *testing.T) {
+	withTmpDir(t, "test-bundle", func(t *testing.T, dir string) {
+		var bundle bytes.Buffer
+		gzw := gzip.NewWriter(&bundle)
+		tw := tar.NewWriter(gzw)
+		assert.NoError(t, addBundleData(tw, bundleBrowsersFile, []byte("{}")))
+		assert.NoError(t, tw.Close())
+		assert.NoError(t, gzw.Close())
+
+		lc, err := NewLifecycle(&LifecycleConfig{UseDrivers: true, Quiet: true, ConfigDir: dir})
+		assert.NoError(t, err)
+		assert.EqualError(t, lc.InstallBundle(&bundle, BundleOptions{}), "invalid bundle: metadata is missing")
+		assert.False(t, fileExists(getSelenoidConfigPath(dir)))
+	})
+}
+
+func TestRelocateConfig(t *testing.T) {
+	data, err := relocateConfig([]byte(`{"chrome": {"default": "latest", "versions": {"latest": {"image": ["/tmp/bundle/selenoid/chromedriver", "--port=4444"], "path": "/"}}}}`), "/tmp/bundle/selenoid", "/home/user/.aerokube/selenoid")
+	assert.NoError(t, err)
+	var cfg SelenoidConfig
+	assert.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, []interface{}{"/home/user/.aerokube/selenoid/chromedriver", "--port=4444"}, cfg["chrome"].Versions[Latest].Image)
+}
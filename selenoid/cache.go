@@ -0,0 +1,332 @@
+package selenoid
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"gopkg.in/cheggaaa/pb.v1"
+)
+
+const (
+	partialFileSuffix  = ".part"
+	partialStateSuffix = ".json"
+	releaseFileSuffix  = ".release.json"
+)
+
+var cacheDirElem = []string{".aerokube", "cache"}
+
+// GetCacheDir returns default location of downloaded binaries and drivers cache
+func GetCacheDir() string {
+	return joinPaths(getHomeDir(), cacheDirElem)
+}
+
+func cacheDirOrDefault(cacheDir string, configDir string) string {
+	if cacheDir != "" {
+		return cacheDir
+	}
+	return filepath.Join(configDir, "cache")
+}
+
+// downloadCache stores downloaded files by URL and expected checksum.
+// Interrupted downloads are resumed with HTTP range requests.
+type downloadCache struct {
+	dir    string
+	retry  RetryPolicy
+	logger *Logger
+}
+
+func cacheKey(url string, checksum string) string {
+	h := sha256.Sum256([]byte(url + "\n" + strings.ToLower(checksum)))
+	return hex.EncodeToString(h[:])
+}
+
+// fetch returns path to cached file downloading it when needed
+func (c *downloadCache) fetch(url string, checksum string) (string, error) {
+	path := filepath.Join(c.dir, cacheKey(url, checksum))
+	if fileExists(path) {
+		err := verifyChecksum(path, checksum)
+		if err == nil {
+			c.logger.Pointf("Using cached file for %s", color.BlueString(url))
+			return path, nil
+		}
+		c.logger.Errorf("Removing corrupted cached file %s: %v", path, err)
+		_ = os.Remove(path)
+	}
+	partialPath, err := c.download(url, path, checksum != "")
+	if err != nil {
+		return "", err
+	}
+	err = verifyChecksum(partialPath, checksum)
+	if err != nil {
+		removePartialFile(partialPath)
+		return "", err
+	}
+	return path, completePartialFile(partialPath, path)
+}
+
+// refresh downloads file again and falls back to cached copy when it is not available
+func (c *downloadCache) refresh(url string) (string, error) {
+	path := filepath.Join(c.dir, cacheKey(url, ""))
+	removePartialFile(path + partialFileSuffix)
+	partialPath, err := c.download(url, path, false)
+	if err != nil {
+		if fileExists(path) {
+			c.logger.Errorf("Failed to download %s, using cached copy: %v", url, err)
+			return path, nil
+		}
+		return "", err
+	}
+	return path, completePartialFile(partialPath, path)
+}
+
+// download resumes partial file, without checksum it is resumed only when it is known to belong to the same remote file
+func (c *downloadCache) download(url string, path string, verifiable bool) (string, error) {
+	err := os.MkdirAll(c.dir, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	partialPath := path + partialFileSuffix
+	err = c.retry.do(context.Background(), c.logger, func() error {
+		return downloadPartialFile(url, partialPath, verifiable)
+	})
+	if err != nil {
+		return "", err
+	}
+	return partialPath, nil
+}
+
+// saveRelease remembers resolved release asset next to downloaded files
+func (c *downloadCache) saveRelease(key string, asset *releaseAsset) {
+	data, err := json.Marshal(asset)
+	if err == nil {
+		err = os.MkdirAll(c.dir, os.ModePerm)
+	}
+	if err == nil {
+		err = os.WriteFile(c.releasePath(key), data, 0644)
+	}
+	if err != nil {
+		c.logger.Errorf("Failed to cache release information: %v", err)
+	}
+}
+
+// release returns cached release asset or nil when release was not resolved before
+func (c *downloadCache) release(key string) *releaseAsset {
+	data, err := os.ReadFile(c.releasePath(key))
+	if err != nil {
+		return nil
+	}
+	var asset releaseAsset
+	if json.Unmarshal(data, &asset) != nil || asset.URL == "" {
+		return nil
+	}
+	return &asset
+}
+
+func (c *downloadCache) releasePath(key string) string {
+	return filepath.Join(c.dir, cacheKey(key, "")+releaseFileSuffix)
+}
+
+// partialState identifies remote file being downloaded, so that partial file is not resumed with another file contents
+type partialState struct {
+	Validator string `json:"validator,omitempty"`
+	Size      int64  `json:"size,omitempty"`
+}
+
+func newPartialState(resp *http.Response) *partialState {
+	validator := resp.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		// Weak entity tags can not be used in If-Range header
+		validator = resp.Header.Get("Last-Modified")
+	}
+	state := &partialState{Validator: validator}
+	if resp.ContentLength > 0 {
+		state.Size = resp.ContentLength
+	}
+	return state
+}
+
+func readPartialState(path string) *partialState {
+	state := &partialState{}
+	data, err := os.ReadFile(path + partialStateSuffix)
+	if err == nil {
+		_ = json.Unmarshal(data, state)
+	}
+	return state
+}
+
+func writePartialState(path string, state *partialState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+partialStateSuffix, data, 0644)
+}
+
+func removePartialFile(path string) {
+	_ = os.Remove(path)
+	_ = os.Remove(path + partialStateSuffix)
+}
+
+func completePartialFile(partialPath string, path string) error {
+	_ = os.Remove(partialPath + partialStateSuffix)
+	return os.Rename(partialPath, path)
+}
+
+// contentRangeSize returns complete size from Content-Range header like "bytes 300-999/1000"
+func contentRangeSize(resp *http.Response) int64 {
+	_, size, _ := strings.Cut(resp.Header.Get("Content-Range"), "/")
+	ret, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return ret
+}
+
+// downloadPartialFile appends missing part of remote file to the local one
+func downloadPartialFile(url string, path string, verifiable bool) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	state := readPartialState(path)
+	known := state.Validator != "" || state.Size > 0
+	if offset > 0 && !verifiable && !known {
+		// Nothing proves that partial file belongs to the same remote file
+		offset = 0
+		err = f.Truncate(0)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if state.Validator != "" {
+			// Server sends the whole file instead of requested range when file was changed
+			req.Header.Set("If-Range", state.Validator)
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("file download error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	restart := func() error {
+		_ = resp.Body.Close()
+		err := f.Truncate(0)
+		if err != nil {
+			return err
+		}
+		_ = os.Remove(path + partialStateSuffix)
+		return downloadPartialFile(url, path, verifiable)
+	}
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if state.Size > 0 && contentRangeSize(resp) != state.Size {
+			return restart()
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		if !verifiable && state.Size != offset {
+			return restart()
+		}
+		// Partial file is already complete, checksum verification will reveal it otherwise
+		return nil
+	case resp.StatusCode == http.StatusOK:
+		offset = 0
+		err = f.Truncate(0)
+		if err != nil {
+			return err
+		}
+		_, err = f.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		err = writePartialState(path, newPartialState(resp))
+		if err != nil {
+			return fmt.Errorf("failed to save download state: %v", err)
+		}
+	default:
+		return newHttpStatusError(resp)
+	}
+
+	var writer io.Writer = f
+	if resp.ContentLength > 0 {
+		bar := pb.New64(offset + resp.ContentLength).SetUnits(pb.U_BYTES)
+		bar.Set64(offset)
+		bar.Output = os.Stderr
+		bar.Start()
+		defer bar.Finish()
+		writer = io.MultiWriter(f, bar)
+	}
+	_, err = io.Copy(writer, resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to save file: %v", err)
+	}
+	return nil
+}
+
+func verifyChecksum(path string, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("failed to calculate checksum: %v", err)
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch: expected sha256 %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// parseChecksums parses sha256sum output, i.e. "<checksum> <file name>" lines
+func parseChecksums(data []byte) map[string]string {
+	ret := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 1 {
+			ret[""] = fields[0]
+		} else if len(fields) >= 2 {
+			ret[strings.TrimPrefix(fields[1], "*")] = fields[0]
+		}
+	}
+	return ret
+}
+
+func isChecksumAsset(name string) bool {
+	lowerName := strings.ToLower(name)
+	return strings.HasSuffix(lowerName, ".sha256") ||
+		strings.HasSuffix(lowerName, "checksums.txt") ||
+		strings.HasSuffix(lowerName, "sha256sums") ||
+		strings.HasSuffix(lowerName, "sha256sums.txt")
+}
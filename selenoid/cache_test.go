@@ -0,0 +1,191 @@
+package selenoid
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var cachedContent = []byte(strings.Repeat("0123456789", 100))
+
+const testETag = `"v1"`
+
+func cachedContentChecksum() string {
+	return fmt.Sprintf("%x", sha256.Sum256(cachedContent))
+}
+
+type rangeServer struct {
+	*httptest.Server
+	requests int32
+	ranges   []string
+	truncate int32
+}
+
+func newRangeServer() *rangeServer {
+	s := &rangeServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+		if atomic.AddInt32(&s.truncate, -1) >= 0 {
+			w.Header().Set("Content-Length", fmt.Sprint(len(cachedContent)))
+			_, _ = w.Write(cachedContent[:len(cachedContent)/2])
+			return
+		}
+		w.Header().Set("ETag", testETag)
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(cachedContent))
+	}))
+	return s
+}
+
+func testCache(dir string) *downloadCache {
+	return &downloadCache{dir: dir, retry: testRetryPolicy, logger: &Logger{}}
+}
+
+func TestCacheFetch(t *testing.T) {
+	withTmpDir(t, "test-cache", func(t *testing.T, dir string) {
+		srv := newRangeServer()
+		defer srv.Close()
+		cache := testCache(dir)
+
+		path, err := cache.fetch(srv.URL+"/file", cachedContentChecksum())
+		assert.NoError(t, err)
+		assert.Equal(t, cachedContent, readFile(t, path))
+
+		cachedPath, err := cache.fetch(srv.URL+"/file", cachedContentChecksum())
+		assert.NoError(t, err)
+		assert.Equal(t, path, cachedPath)
+		assert.Equal(t, int32(1), srv.requests)
+
+		_, err = cache.fetch(srv.URL+"/file", "")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), srv.requests)
+	})
+}
+
+func TestCacheResumePartialFile(t *testing.T) {
+	withTmpDir(t, "test-cache", func(t *testing.T, dir string) {
+		srv := newRangeServer()
+		defer srv.Close()
+		cache := testCache(dir)
+
+		u := srv.URL + "/file"
+		partialPath := filepath.Join(dir, cacheKey(u, "")) + partialFileSuffix
+		_ = os.WriteFile(partialPath, cachedContent[:300], 0644)
+		assert.NoError(t, writePartialState(partialPath, &partialState{Validator: testETag}))
+
+		path, err := cache.fetch(u, "")
+		assert.NoError(t, err)
+		assert.Equal(t, cachedContent, readFile(t, path))
+		assert.Equal(t, []string{"bytes=300-"}, srv.ranges)
+		assert.False(t, fileExists(partialPath))
+		assert.False(t, fileExists(partialPath+partialStateSuffix))
+	})
+}
+
+func TestCacheDiscardUnknownPartialFile(t *testing.T) {
+	withTmpDir(t, "test-cache", func(t *testing.T, dir string) {
+		srv := newRangeServer()
+		defer srv.Close()
+		cache := testCache(dir)
+
+		u := srv.URL + "/file"
+		partialPath := filepath.Join(dir, cacheKey(u, "")) + partialFileSuffix
+		_ = os.WriteFile(partialPath, []byte("other file contents"), 0644)
+		path, err := cache.fetch(u, "")
+		assert.NoError(t, err)
+		assert.Equal(t, cachedContent, readFile(t, path))
+		assert.Equal(t, []string{""}, srv.ranges)
+
+		_ = os.Remove(path)
+		_ = os.WriteFile(partialPath, []byte("other file contents"), 0644)
+		assert.NoError(t, writePartialState(partialPath, &partialState{Validator: `"v0"`}))
+		path, err = cache.fetch(u, "")
+		assert.NoError(t, err)
+		assert.Equal(t, cachedContent, readFile(t, path))
+	})
+}
+
+func TestCacheResumeTruncatedDownload(t *testing.T) {
+	withTmpDir(t, "test-cache", func(t *testing.T, dir string) {
+		srv := newRangeServer()
+		srv.truncate = 1
+		defer srv.Close()
+
+		path, err := testCache(dir).fetch(srv.URL+"/file", cachedContentChecksum())
+		assert.NoError(t, err)
+		assert.Equal(t, cachedContent, readFile(t, path))
+		assert.Equal(t, []string{"", "bytes=500-"}, srv.ranges)
+	})
+}
+
+func TestCacheChecksumMismatch(t *testing.T) {
+	withTmpDir(t, "test-cache", func(t *testing.T, dir string) {
+		srv := newRangeServer()
+		defer srv.Close()
+		cache := testCache(dir)
+
+		wrongChecksum := strings.Repeat("0", 64)
+		_, err := cache.fetch(srv.URL+"/file", wrongChecksum)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum mismatch")
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+
+		path, err := cache.fetch(srv.URL+"/file", strings.ToUpper(cachedContentChecksum()))
+		assert.NoError(t, err)
+		_ = os.WriteFile(path, []byte("corrupted"), 0644)
+		path, err = cache.fetch(srv.URL+"/file", cachedContentChecksum())
+		assert.NoError(t, err)
+		assert.Equal(t, cachedContent, readFile(t, path))
+		assert.Equal(t, int32(3), srv.requests)
+	})
+}
+
+func TestCacheRefresh(t *testing.T) {
+	withTmpDir(t, "test-cache", func(t *testing.T, dir string) {
+		srv := newRangeServer()
+		cache := testCache(dir)
+		u := srv.URL + "/file"
+
+		path, err := cache.refresh(u)
+		assert.NoError(t, err)
+		_, err = cache.refresh(u)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), srv.requests)
+
+		srv.Close()
+		cachedPath, err := cache.refresh(u)
+		assert.NoError(t, err)
+		assert.Equal(t, path, cachedPath)
+		assert.Equal(t, cachedContent, readFile(t, cachedPath))
+
+		_, err = cache.refresh(u + "/missing")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseChecksums(t *testing.T) {
+	checksums := parseChecksums([]byte("abc  selenoid_linux_amd64\ndef *selenoid_darwin_amd64\n\n"))
+	assert.Equal(t, map[string]string{
+		"selenoid_linux_amd64":  "abc",
+		"selenoid_darwin_amd64": "def",
+	}, checksums)
+	assert.Equal(t, map[string]string{"": "abc"}, parseChecksums([]byte("abc\n")))
+}
+
+func TestIsChecksumAsset(t *testing.T) {
+	assert.True(t, isChecksumAsset("checksums.txt"))
+	assert.True(t, isChecksumAsset("selenoid_linux_amd64.sha256"))
+	assert.True(t, isChecksumAsset("SHA256SUMS"))
+	assert.False(t, isChecksumAsset("selenoid_linux_amd64"))
+}
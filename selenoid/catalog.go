@@ -0,0 +1,126 @@
+package selenoid
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultBrowserPort = "4444"
+	defaultBrowserPath = "/"
+	webDriverHubPath   = "/wd/hub"
+)
+
+// Catalog maps browser names to Docker images and settings used to generate browsers.json
+type Catalog map[string]*CatalogBrowser
+
+type CatalogBrowser struct {
+	Image string `json:"image" yaml:"image"`
+	// Default browsers are processed when no browsers were explicitly requested
+	Default bool              `json:"default,omitempty" yaml:"default,omitempty"`
+	Path    string            `json:"path,omitempty" yaml:"path,omitempty"`
+	Port    string            `json:"port,omitempty" yaml:"port,omitempty"`
+	Tmpfs   map[string]string `json:"tmpfs,omitempty" yaml:"tmpfs,omitempty"`
+	Env     []string          `json:"env,omitempty" yaml:"env,omitempty"`
+	// Paths overrides path for particular image tags
+	Paths map[string]string `json:"paths,omitempty" yaml:"paths,omitempty"`
+}
+
+// DefaultCatalog returns browser images maintained by Aerokube
+func DefaultCatalog() Catalog {
+	return Catalog{
+		firefox:  {Image: "selenoid/firefox", Default: true, Path: webDriverHubPath},
+		"chrome": {Image: "selenoid/chrome", Default: true},
+		opera: {
+			Image:   "selenoid/opera",
+			Default: true,
+			Paths:   map[string]string{tag_1216: webDriverHubPath},
+		},
+		android: {Image: "selenoid/android", Path: webDriverHubPath},
+		edge:    {Image: "browsers/edge"},
+	}
+}
+
+// LoadCatalog reads catalog in JSON or YAML format from a local file or an HTTP(S) URL
+func LoadCatalog(location string, retry RetryPolicy) (Catalog, error) {
+	data, name, err := readCatalog(location, retry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read browsers catalog from %s: %v", location, err)
+	}
+	var catalog Catalog
+	err = unmarshalFile(name, data, &catalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse browsers catalog from %s: %v", location, err)
+	}
+	if err := catalog.validate(); err != nil {
+		return nil, fmt.Errorf("invalid browsers catalog %s: %v", location, err)
+	}
+	return catalog, nil
+}
+
+func readCatalog(location string, retry RetryPolicy) ([]byte, string, error) {
+	u, err := url.Parse(location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		data, err := os.ReadFile(location)
+		return data, location, err
+	}
+	data, err := httpGet(retry.httpClient(nil), location)
+	return data, path.Base(u.Path), err
+}
+
+func httpGet(client *http.Client, location string) ([]byte, error) {
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHttpStatusError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (c Catalog) validate() error {
+	if len(c) == 0 {
+		return fmt.Errorf("no browsers defined")
+	}
+	for name, browser := range c {
+		if browser == nil || strings.TrimSpace(browser.Image) == "" {
+			return fmt.Errorf("no image specified for browser %s", name)
+		}
+	}
+	return nil
+}
+
+// Names returns sorted browser names
+func (c Catalog) Names() []string {
+	var ret []string
+	for name := range c {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (b *CatalogBrowser) path(tag string) string {
+	if p, ok := b.Paths[tag]; ok {
+		return p
+	}
+	if b.Path != "" {
+		return b.Path
+	}
+	return defaultBrowserPath
+}
+
+func (b *CatalogBrowser) port() string {
+	if b.Port != "" {
+		return b.Port
+	}
+	return defaultBrowserPort
+}
@@ -0,0 +1,136 @@
+package selenoid
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerokube/selenoid/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+const testCatalog = `
+firefox:
+  image: selenoid/firefox
+  default: true
+  path: /custom
+  port: "5555"
+  tmpfs:
+    /tmp: size=128m
+    /var: size=64m
+  env: ["TZ=UTC"]
+opera:
+  image: selenoid/opera
+`
+
+func TestDefaultCatalog(t *testing.T) {
+	catalog := DefaultCatalog()
+	assert.Equal(t, []string{edge, android, "chrome", firefox, opera}, catalog.Names())
+	assert.Equal(t, "/wd/hub", catalog[firefox].path("46.0"))
+	assert.Equal(t, "/wd/hub", catalog[android].path("10.0"))
+	assert.Equal(t, "/", catalog["chrome"].path("120.0"))
+	assert.Equal(t, "/", catalog[opera].path("44.0"))
+	assert.Equal(t, "/wd/hub", catalog[opera].path("12.16"))
+	assert.Equal(t, "4444", catalog[edge].port())
+	assert.False(t, catalog[android].Default)
+	assert.False(t, catalog[edge].Default)
+}
+
+func TestLoadCatalog(t *testing.T) {
+	withTmpDir(t, "test-catalog", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "catalog.yml")
+		_ = os.WriteFile(path, []byte(testCatalog), 0644)
+		catalog, err := LoadCatalog(path, RetryPolicy{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{firefox, opera}, catalog.Names())
+		assert.Equal(t, "/custom", catalog[firefox].path("46.0"))
+		assert.Equal(t, "5555", catalog[firefox].port())
+		assert.True(t, catalog[firefox].Default)
+		assert.False(t, catalog[opera].Default)
+	})
+}
+
+func TestLoadCatalogFromUrl(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprintln(w, `{"chrome": {"image": "example.com/chrome", "default": true}}`)
+	}))
+	defer srv.Close()
+
+	catalog, err := LoadCatalog(srv.URL+"/catalog.json", RetryPolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/chrome", catalog["chrome"].Image)
+
+	_, err = LoadCatalog(srv.URL+"/missing.json", RetryPolicy{})
+	assert.Error(t, err)
+}
+
+func TestLoadInvalidCatalog(t *testing.T) {
+	withTmpDir(t, "test-catalog", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "catalog.json")
+		_ = os.WriteFile(path, []byte(`{"chrome": {"default": true}}`), 0644)
+		_, err := LoadCatalog(path, RetryPolicy{})
+		assert.Error(t, err)
+
+		_, err = LoadCatalog(filepath.Join(dir, "missing.json"), RetryPolicy{})
+		assert.Error(t, err)
+	})
+}
+
+func TestConfigureWithCatalog(t *testing.T) {
+	withTmpDir(t, "test-docker-catalog", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "catalog.yml")
+		_ = os.WriteFile(path, []byte(testCatalog), 0644)
+
+		lcConfig := LifecycleConfig{
+			ConfigDir:    dir,
+			RegistryUrl:  mockDockerServer.URL,
+			Catalog:      path,
+			LastVersions: 1,
+			Tmpfs:        512,
+			BrowserEnv:   []string{testEnv},
+		}
+		c, err := NewDockerConfigurator(&lcConfig)
+		assert.NoError(t, err)
+		defer c.Close()
+		cfgPointer, err := c.Configure()
+		assert.NoError(t, err)
+
+		cfg := *cfgPointer
+		assert.Len(t, cfg, 1)
+		assert.Equal(t, config.Versions{
+			Default: "46.0",
+			Versions: map[string]*config.Browser{
+				"46.0": {
+					Image: c.getFullyQualifiedImageRef("selenoid/firefox:46.0"),
+					Port:  "5555",
+					Path:  "/custom",
+					Tmpfs: map[string]string{"/tmp": "size=512m", "/var": "size=64m"},
+					Env:   []string{"TZ=UTC", testEnv},
+				},
+			},
+		}, cfg[firefox])
+
+		c.Browsers = "chrome;opera"
+		cfgPointer, err = c.Configure()
+		assert.NoError(t, err)
+		cfg = *cfgPointer
+		assert.Len(t, cfg, 1)
+		assert.Contains(t, cfg, opera)
+	})
+}
+
+func TestFullyQualifiedCatalogImage(t *testing.T) {
+	c := &DockerConfigurator{registryHost: "registry.example.com"}
+	assert.Equal(t, "registry.example.com/selenoid/chrome", c.getFullyQualifiedImageRef("selenoid/chrome"))
+	assert.Equal(t, "registry.example.com/ubuntu:22.04", c.getFullyQualifiedImageRef("ubuntu:22.04"))
+	assert.Equal(t, "example.com/chrome", c.getFullyQualifiedImageRef("example.com/chrome"))
+	assert.Equal(t, "localhost/chrome", c.getFullyQualifiedImageRef("localhost/chrome"))
+	assert.Equal(t, "registry:5000/chrome:120.0", c.getFullyQualifiedImageRef("registry:5000/chrome:120.0"))
+}
@@ -0,0 +1,257 @@
+package selenoid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	"github.com/fatih/color"
+)
+
+type CleanupOptions struct {
+	UI         bool
+	Images     bool
+	Network    bool
+	KeepVideos bool
+	KeepLogs   bool
+}
+
+// CleanupReport lists everything removed by cleanup
+type CleanupReport struct {
+	Containers []string `json:"containers,omitempty"`
+	Network    string   `json:"network,omitempty"`
+	Images     []string `json:"images,omitempty"`
+	Files      []string `json:"files,omitempty"`
+}
+
+// Cleanup removes orphaned browser containers and optionally Selenoid network and images
+func (c *DockerConfigurator) Cleanup(opts CleanupOptions) (*CleanupReport, error) {
+	report := &CleanupReport{}
+	if opts.UI {
+		return report, nil
+	}
+	containers, err := c.removeOrphanedContainers()
+	if err != nil {
+		return nil, err
+	}
+	report.Containers = containers
+	if opts.Images {
+		report.Images = c.removeImages(c.cleanupImages())
+	}
+	if opts.Network {
+		report.Network = c.removeNetwork()
+	}
+	return report, nil
+}
+
+// removeOrphanedContainers removes browser containers left on Selenoid network after Selenoid was stopped,
+// other containers attached to the network are kept
+func (c *DockerConfigurator) removeOrphanedContainers() ([]string, error) {
+	ctx := context.Background()
+	f := filters.NewArgs()
+	f.Add("network", c.instanceName(networkName))
+	containers, err := c.docker.ContainerList(ctx, container.ListOptions{All: true, Filters: f})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %v", err)
+	}
+	if len(containers) == 0 {
+		return nil, nil
+	}
+	repositories, labels := c.browserContainers()
+	var ret []string
+	for _, ctr := range containers {
+		name := ctr.ID
+		if len(ctr.Names) > 0 {
+			name = strings.TrimPrefix(ctr.Names[0], "/")
+		}
+		if !isBrowserContainer(ctr, repositories, labels) {
+			continue
+		}
+		err := c.docker.ContainerRemove(ctx, ctr.ID, container.RemoveOptions{RemoveVolumes: true, Force: true})
+		if err != nil {
+			c.Errorf("Failed to remove container %s: %v", name, err)
+			continue
+		}
+		ret = append(ret, name)
+	}
+	return ret, nil
+}
+
+// browserContainers returns image repositories from browsers.json and catalog and container labels from browsers.json
+func (c *DockerConfigurator) browserContainers() (map[string]struct{}, []map[string]string) {
+	repositories := make(map[string]struct{})
+	var labels []map[string]string
+	cfg, err := c.currentConfig()
+	if err != nil {
+		c.Errorf("Failed to read browsers.json: %v", err)
+	}
+	for _, ref := range append(browserImages(&cfg), c.getFullyQualifiedImageRef(videoRecorderImage)) {
+		repositories[normalizedRepositoryName(ref)] = struct{}{}
+	}
+	for _, versions := range cfg {
+		for _, browser := range versions.Versions {
+			if len(browser.Labels) > 0 {
+				labels = append(labels, browser.Labels)
+			}
+		}
+	}
+	catalog, err := c.loadCatalog()
+	if err != nil {
+		c.Errorf("Failed to load browsers catalog: %v", err)
+		return repositories, labels
+	}
+	for _, browser := range c.getBrowsersToIterate(catalog, parseRequestedBrowsers(&c.Logger, c.Browsers)) {
+		repositories[normalizedRepositoryName(c.getFullyQualifiedImageRef(browser.Image))] = struct{}{}
+	}
+	return repositories, labels
+}
+
+// isBrowserContainer checks that container was started from browser image or has all labels of some browser
+func isBrowserContainer(ctr types.Container, repositories map[string]struct{}, labels []map[string]string) bool {
+	if _, ok := repositories[normalizedRepositoryName(ctr.Image)]; ok {
+		return true
+	}
+	for _, browserLabels := range labels {
+		matches := true
+		for k, v := range browserLabels {
+			if value, ok := ctr.Labels[k]; !ok || value != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupImages returns browser images from browsers.json and video recorder image
+func (c *DockerConfigurator) cleanupImages() []string {
+	var ret []string
+	data, err := os.ReadFile(getSelenoidConfigPath(c.ConfigDir))
+	if err == nil {
+		var cfg SelenoidConfig
+		if json.Unmarshal(data, &cfg) == nil {
+			ret = browserImages(&cfg)
+		}
+	}
+	return uniqueStrings(append(ret, c.getFullyQualifiedImageRef(videoRecorderImage)))
+}
+
+func (c *DockerConfigurator) removeImages(refs []string) []string {
+	var ret []string
+	for _, ref := range refs {
+		_, err := c.docker.ImageRemove(context.Background(), ref, image.RemoveOptions{PruneChildren: true})
+		if errdefs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			c.Errorf("Failed to remove image %s: %v", ref, err)
+			continue
+		}
+		ret = append(ret, ref)
+	}
+	return ret
+}
+
+func (c *DockerConfigurator) removeNetwork() string {
+	ctx := context.Background()
+	name := c.instanceName(networkName)
+	if _, err := c.docker.NetworkInspect(ctx, name, types.NetworkInspectOptions{}); err != nil {
+		return ""
+	}
+	err := c.docker.NetworkRemove(ctx, name)
+	if err != nil {
+		c.Errorf("Failed to remove network %s (is Selenoid UI still running?): %v", name, err)
+		return ""
+	}
+	return name
+}
+
+// Cleanup removes Docker resources and configuration directory keeping videos and logs when requested
+func (l *Lifecycle) Cleanup(opts CleanupOptions) (*CleanupReport, error) {
+	report := &CleanupReport{}
+	if l.cleaner != nil {
+		r, err := l.cleaner.Cleanup(opts)
+		if err != nil {
+			return nil, err
+		}
+		report = r
+	} else if opts.Images || opts.Network {
+		return nil, errors.New("removing images and network is supported in Docker mode only")
+	}
+	keep := make(map[string]struct{})
+	if opts.KeepVideos {
+		keep[videoDirName] = struct{}{}
+	}
+	if opts.KeepLogs {
+		keep[logsDirName] = struct{}{}
+	}
+	files, err := removeDir(l.Config.ConfigDir, keep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove configuration directory: %v", err)
+	}
+	report.Files = files
+	return report, nil
+}
+
+// removeDir removes directory contents except specified entries and the directory itself when nothing was kept
+func removeDir(dir string, keep map[string]struct{}) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(keep) == 0 {
+		return []string{dir}, os.RemoveAll(dir)
+	}
+	var ret []string
+	kept := false
+	for _, entry := range entries {
+		if _, ok := keep[entry.Name()]; ok {
+			kept = true
+			continue
+		}
+		p := filepath.Join(dir, entry.Name())
+		err = os.RemoveAll(p)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, p)
+	}
+	if !kept {
+		return append(ret, dir), os.Remove(dir)
+	}
+	return ret, nil
+}
+
+func (l *Lifecycle) PrintCleanupReport(report *CleanupReport) {
+	l.Titlef("Cleanup summary:")
+	for _, name := range report.Containers {
+		l.Pointf("Removed container %v", color.BlueString(name))
+	}
+	if report.Network != "" {
+		l.Pointf("Removed network %v", color.BlueString(report.Network))
+	}
+	for _, ref := range report.Images {
+		l.Pointf("Removed image %v", color.BlueString(ref))
+	}
+	for _, file := range report.Files {
+		l.Pointf("Removed %v", color.BlueString(file))
+	}
+	if len(report.Containers) == 0 && report.Network == "" && len(report.Images) == 0 && len(report.Files) == 0 {
+		l.Pointf("Nothing to remove")
+	}
+}
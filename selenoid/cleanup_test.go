@@ -0,0 +1,115 @@
+package selenoid
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	assert "github.com/stretchr/testify/require"
+)
+
+func mockCleanupDockerServer(removed *requestRecorder) *httptest.Server {
+	return mockDockerServerWith(func(w http.ResponseWriter, r *http.Request) bool {
+		switch {
+		case r.URL.Path == "/v1.29/containers/json" && strings.Contains(r.URL.Query().Get("filters"), "network"):
+			_, _ = fmt.Fprint(w, `[
+				{"Id": "e90e34656806", "Names": ["/selenoid-ui"], "Image": "aerokube/selenoid-ui:latest-release"},
+				{"Id": "a1b2c3d4e5f6", "Names": ["/amazing_browser"], "Image": "selenoid/firefox:46.0"},
+				{"Id": "b2c3d4e5f6a1", "Names": ["/labeled_browser"], "Image": "sha256:b2c3d4", "Labels": {"team": "qa", "name": "test"}},
+				{"Id": "c3d4e5f6a1b2", "Names": ["/database"], "Image": "postgres:16", "Labels": {"name": "test"}}
+			]`)
+		case r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "missing"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"message": "No such image"}`)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1.29/images/"):
+			removed.record(strings.TrimPrefix(r.URL.Path, "/v1.29/"))
+			_, _ = fmt.Fprint(w, `[{"Untagged": "image"}]`)
+		case r.Method == http.MethodDelete:
+			removed.record(strings.TrimPrefix(r.URL.Path, "/v1.29/"))
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/v1.29/networks/selenoid":
+			_, _ = fmt.Fprint(w, `{"Name": "selenoid", "Id": "39d591dabe31"}`)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+func TestDockerCleanup(t *testing.T) {
+	var removed requestRecorder
+	srv := mockCleanupDockerServer(&removed)
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	withTmpDir(t, "test-cleanup", func(t *testing.T, dir string) {
+		data := `{
+			"firefox": {"default": "46.0", "versions": {"46.0": {"image": "selenoid/firefox:46.0", "labels": {"team": "qa"}}}},
+			"opera": {"default": "33.0", "versions": {"33.0": {"image": "selenoid/missing:33.0"}}}
+		}`
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(data), 0644))
+
+		c, err := NewDockerConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		report, err := c.Cleanup(CleanupOptions{UI: true, Images: true, Network: true})
+		assert.NoError(t, err)
+		assert.Equal(t, &CleanupReport{}, report)
+		assert.Empty(t, removed.recorded())
+
+		report, err = c.Cleanup(CleanupOptions{Images: true, Network: true})
+		assert.NoError(t, err)
+		assert.Equal(t, &CleanupReport{
+			Containers: []string{"amazing_browser", "labeled_browser"},
+			Network:    "selenoid",
+			Images:     []string{"selenoid/firefox:46.0", videoRecorderImage},
+		}, report)
+		assert.Equal(t, []string{
+			"containers/a1b2c3d4e5f6",
+			"containers/b2c3d4e5f6a1",
+			"images/selenoid/firefox:46.0",
+			"images/" + videoRecorderImage,
+			"networks/selenoid",
+		}, removed.recorded())
+	})
+}
+
+func TestIsBrowserContainer(t *testing.T) {
+	repositories := map[string]struct{}{"selenoid/chrome": {}}
+	labels := []map[string]string{{"team": "qa", "project": "web"}}
+	assert.True(t, isBrowserContainer(types.Container{Image: "docker.io/selenoid/chrome:120.0"}, repositories, labels))
+	assert.True(t, isBrowserContainer(types.Container{Image: "selenoid/chrome@sha256:0"}, repositories, labels))
+	assert.True(t, isBrowserContainer(types.Container{Image: "sha256:0", Labels: map[string]string{"team": "qa", "project": "web", "name": "test"}}, repositories, labels))
+	assert.False(t, isBrowserContainer(types.Container{Image: "sha256:0", Labels: map[string]string{"team": "qa"}}, repositories, labels))
+	assert.False(t, isBrowserContainer(types.Container{Image: "postgres:16"}, repositories, labels))
+}
+
+func TestCleanupKeepsVideosAndLogs(t *testing.T) {
+	withTmpDir(t, "test-cleanup-keep", func(t *testing.T, dir string) {
+		for _, name := range []string{videoDirName, logsDirName} {
+			assert.NoError(t, os.MkdirAll(filepath.Join(dir, name), os.ModePerm))
+		}
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte("{}"), 0644))
+
+		l := &Lifecycle{Config: &LifecycleConfig{ConfigDir: dir}}
+		report, err := l.Cleanup(CleanupOptions{KeepVideos: true})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{getSelenoidConfigPath(dir), filepath.Join(dir, logsDirName)}, report.Files)
+		assert.DirExists(t, filepath.Join(dir, videoDirName))
+		assert.NoFileExists(t, getSelenoidConfigPath(dir))
+
+		report, err = l.Cleanup(CleanupOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{dir}, report.Files)
+		assert.NoDirExists(t, dir)
+
+		_, err = l.Cleanup(CleanupOptions{Network: true})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package selenoid
+
+import "syscall"
+
+// detachedProcAttr starts process in a new session so that it survives terminal close
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package selenoid
+
+import "syscall"
+
+const detachedProcess = 0x00000008
+
+// detachedProcAttr starts process without console so that it survives terminal close
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess}
+}
@@ -0,0 +1,192 @@
+package selenoid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aerokube/selenoid/config"
+	"github.com/fatih/color"
+)
+
+type DigestState string
+
+const (
+	DigestMatches  DigestState = "ok"
+	DigestDrifted  DigestState = "drifted"
+	DigestMissing  DigestState = "missing"
+	DigestUnpinned DigestState = "unpinned"
+	// DigestUntagged means that pinned image exists but local tag it was pinned from is gone, this is not a drift
+	// as Selenoid uses pinned image only
+	DigestUntagged DigestState = "untagged"
+
+	digestSeparator = "@"
+)
+
+// ImageDigestStatus compares browser image pinned in browsers.json with local one
+type ImageDigestStatus struct {
+	Browser string      `json:"browser"`
+	Version string      `json:"version"`
+	Image   string      `json:"image"`
+	Local   string      `json:"local,omitempty"`
+	State   DigestState `json:"state"`
+}
+
+func (s *ImageDigestStatus) IsDrifted() bool {
+	return s.State == DigestDrifted || s.State == DigestMissing
+}
+
+// repositoryName strips tag and digest from image reference
+func repositoryName(ref string) string {
+	if i := strings.Index(ref, digestSeparator); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// normalizedRepositoryName removes implicit Docker Hub prefixes, e.g. docker.io/library/ubuntu becomes ubuntu
+func normalizedRepositoryName(ref string) string {
+	name := repositoryName(ref)
+	for _, prefix := range []string{"docker.io/", "index.docker.io/", "library/"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+// imageDigest returns repository digest of local image
+func (c *DockerConfigurator) imageDigest(ref string) (string, error) {
+	img, _, err := c.docker.ImageInspectWithRaw(context.Background(), ref)
+	if err != nil {
+		return "", err
+	}
+	name := normalizedRepositoryName(ref)
+	for _, repoDigest := range img.RepoDigests {
+		pieces := strings.SplitN(repoDigest, digestSeparator, 2)
+		if len(pieces) == 2 && normalizedRepositoryName(pieces[0]) == name {
+			return pieces[1], nil
+		}
+	}
+	return "", fmt.Errorf("image %s has no repository digest", ref)
+}
+
+// pinDigest replaces image tag with repository digest when it is known
+func (c *DockerConfigurator) pinDigest(ref string) string {
+	digest, err := c.imageDigest(ref)
+	if err != nil {
+		c.Errorf("Failed to pin %s to digest, keeping tag: %v", ref, err)
+		return ref
+	}
+	pinned := repositoryName(ref) + digestSeparator + digest
+	c.Pointf("Pinned %s to %s", ref, color.BlueString(pinned))
+	return pinned
+}
+
+func (c *DockerConfigurator) pinDigests(versions config.Versions) {
+	for _, browser := range versions.Versions {
+		if ref, ok := browser.Image.(string); ok {
+			browser.Image = c.pinDigest(ref)
+		}
+	}
+}
+
+// RepinImages checks pinned images after loading them from archive and returns true when configuration changed.
+// Docker loads images without repository digests, so pinned images that can not be found are referred to by tag
+func (c *DockerConfigurator) RepinImages(cfg *SelenoidConfig) bool {
+	changed := false
+	for _, versions := range *cfg {
+		for version, browser := range versions.Versions {
+			ref, ok := browser.Image.(string)
+			if !ok || !strings.Contains(ref, digestSeparator) {
+				continue
+			}
+			if _, _, err := c.docker.ImageInspectWithRaw(context.Background(), ref); err == nil {
+				continue
+			}
+			tagged := imageWithTag(repositoryName(ref), version)
+			c.Pointf("Pinned image %s was loaded without repository digest, using %s", ref, color.BlueString(tagged))
+			browser.Image = tagged
+			changed = true
+		}
+	}
+	return changed
+}
+
+// VerifyDigests checks that local images still match digests pinned in browsers.json
+func (c *DockerConfigurator) VerifyDigests() ([]ImageDigestStatus, error) {
+	configPath := getSelenoidConfigPath(c.ConfigDir)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read browsers.json: %v", err)
+	}
+	var cfg SelenoidConfig
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse browsers.json from %s: %v", configPath, err)
+	}
+	var ret []ImageDigestStatus
+	for name, versions := range cfg {
+		for version, browser := range versions.Versions {
+			ref, ok := browser.Image.(string)
+			if !ok {
+				continue
+			}
+			ret = append(ret, c.verifyDigest(name, version, ref))
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Browser != ret[j].Browser {
+			return ret[i].Browser < ret[j].Browser
+		}
+		return ret[i].Version < ret[j].Version
+	})
+	return ret, nil
+}
+
+func (c *DockerConfigurator) verifyDigest(browser string, version string, ref string) ImageDigestStatus {
+	status := ImageDigestStatus{Browser: browser, Version: version, Image: ref}
+	pieces := strings.SplitN(ref, digestSeparator, 2)
+	if len(pieces) != 2 {
+		status.State = DigestUnpinned
+		return status
+	}
+	repo, pinned := pieces[0], pieces[1]
+	local, localErr := c.imageDigest(imageWithTag(repo, version))
+	if localErr == nil {
+		status.Local = local
+	}
+	_, _, err := c.docker.ImageInspectWithRaw(context.Background(), ref)
+	switch {
+	case err != nil:
+		status.State = DigestMissing
+	case localErr != nil:
+		status.State = DigestUntagged
+	case status.Local != pinned:
+		status.State = DigestDrifted
+	default:
+		status.State = DigestMatches
+	}
+	return status
+}
+
+func (l *Lifecycle) PrintDigests(statuses []ImageDigestStatus) {
+	for _, s := range statuses {
+		switch s.State {
+		case DigestDrifted:
+			l.Errorf("Browser %s %s: pinned to %s but local tag points to %s", s.Browser, s.Version, s.Image, color.RedString(s.Local))
+		case DigestMissing:
+			l.Errorf("Browser %s %s: pinned image %s is %s", s.Browser, s.Version, s.Image, color.RedString("missing"))
+		case DigestUntagged:
+			l.Pointf("Browser %s %s: image %s is up to date but local tag %s is %s", color.GreenString(s.Browser), s.Version, s.Image, imageWithTag(repositoryName(s.Image), s.Version), color.YellowString("missing"))
+		case DigestUnpinned:
+			l.Pointf("Browser %s %s: image %s is not pinned", color.GreenString(s.Browser), s.Version, s.Image)
+		default:
+			l.Pointf("Browser %s %s: image %s is %s", color.GreenString(s.Browser), s.Version, s.Image, color.GreenString("up to date"))
+		}
+	}
+}
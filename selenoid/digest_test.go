@@ -0,0 +1,107 @@
+package selenoid
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func testDigest(repo string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(repo)))
+}
+
+func TestRepositoryName(t *testing.T) {
+	assert.Equal(t, "selenoid/chrome", repositoryName("selenoid/chrome:120.0"))
+	assert.Equal(t, "localhost:5000/selenoid/chrome", repositoryName("localhost:5000/selenoid/chrome:120.0"))
+	assert.Equal(t, "localhost:5000/selenoid/chrome", repositoryName("localhost:5000/selenoid/chrome"))
+	assert.Equal(t, "selenoid/chrome", repositoryName("selenoid/chrome@sha256:abc"))
+	assert.Equal(t, "ubuntu", normalizedRepositoryName("docker.io/library/ubuntu:22.04"))
+}
+
+func TestPinDigests(t *testing.T) {
+	withTmpDir(t, "test-pin-digests", func(t *testing.T, dir string) {
+		c, err := NewDockerConfigurator(&LifecycleConfig{
+			ConfigDir:    dir,
+			RegistryUrl:  mockDockerServer.URL,
+			Download:     true,
+			LastVersions: 1,
+			Browsers:     "firefox;opera",
+			PinDigests:   true,
+		})
+		assert.NoError(t, err)
+		defer c.Close()
+		cfg, err := c.Configure()
+		assert.NoError(t, err)
+
+		firefox := c.getFullyQualifiedImageRef("selenoid/firefox")
+		assert.Equal(t, firefox+"@"+testDigest(firefox), (*cfg)["firefox"].Versions["46.0"].Image)
+
+		statuses, err := c.VerifyDigests()
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 2)
+		for _, s := range statuses {
+			assert.Equal(t, DigestMatches, s.State)
+			assert.False(t, s.IsDrifted())
+		}
+	})
+}
+
+func TestPinDigestsWithBrowsersJson(t *testing.T) {
+	withTmpDir(t, "test-pin-digests-browsers-json", func(t *testing.T, dir string) {
+		browsersJson := filepath.Join(dir, "initial-browsers.json")
+		data := `{"firefox": {"default": "46.0", "versions": {"46.0": {"image": "selenoid/vnc_firefox:46.0"}}}}`
+		assert.NoError(t, os.WriteFile(browsersJson, []byte(data), 0644))
+
+		c, err := NewDockerConfigurator(&LifecycleConfig{
+			ConfigDir:    dir,
+			RegistryUrl:  mockDockerServer.URL,
+			BrowsersJson: browsersJson,
+			Download:     true,
+			PinDigests:   true,
+		})
+		assert.NoError(t, err)
+		defer c.Close()
+		cfg, err := c.Configure()
+		assert.NoError(t, err)
+		assert.Equal(t, "selenoid/vnc_firefox@"+testDigest("selenoid/vnc_firefox"), (*cfg)["firefox"].Versions["46.0"].Image)
+
+		saved, err := os.ReadFile(getSelenoidConfigPath(dir))
+		assert.NoError(t, err)
+		assert.Contains(t, string(saved), "selenoid/vnc_firefox@"+testDigest("selenoid/vnc_firefox"))
+	})
+}
+
+func TestVerifyDigests(t *testing.T) {
+	withTmpDir(t, "test-verify-digests", func(t *testing.T, dir string) {
+		data := fmt.Sprintf(`{
+			"chrome": {"default": "120.0", "versions": {
+				"119.0": {"image": "selenoid/chrome@%s"},
+				"120.0": {"image": "selenoid/chrome@%s"},
+				"missing": {"image": "selenoid/chrome@%s"}
+			}},
+			"firefox": {"default": "121.0", "versions": {"121.0": {"image": "selenoid/firefox:121.0"}}},
+			"opera": {"default": "106.0", "versions": {"106.0": {"image": "selenoid/missing@%s"}}}
+		}`, testDigest("selenoid/chrome"), testDigest("stale"), testDigest("selenoid/chrome"), testDigest("selenoid/missing"))
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(data), 0644))
+
+		c, err := NewDockerConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true})
+		assert.NoError(t, err)
+		defer c.Close()
+		statuses, err := c.VerifyDigests()
+		assert.NoError(t, err)
+		assert.Equal(t, []ImageDigestStatus{
+			{Browser: "chrome", Version: "119.0", Image: "selenoid/chrome@" + testDigest("selenoid/chrome"), Local: testDigest("selenoid/chrome"), State: DigestMatches},
+			{Browser: "chrome", Version: "120.0", Image: "selenoid/chrome@" + testDigest("stale"), Local: testDigest("selenoid/chrome"), State: DigestDrifted},
+			{Browser: "chrome", Version: "missing", Image: "selenoid/chrome@" + testDigest("selenoid/chrome"), State: DigestUntagged},
+			{Browser: "firefox", Version: "121.0", Image: "selenoid/firefox:121.0", State: DigestUnpinned},
+			{Browser: "opera", Version: "106.0", Image: "selenoid/missing@" + testDigest("selenoid/missing"), State: DigestMissing},
+		}, statuses)
+		for _, s := range statuses {
+			assert.Equal(t, s.State == DigestDrifted || s.State == DigestMissing, s.IsDrifted(), s.Version)
+		}
+	})
+}
@@ -3,13 +3,11 @@ package selenoid
 import (
 	"bufio"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -18,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api"
@@ -26,6 +25,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/aerokube/selenoid/config"
 	authconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
 	configtypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -34,13 +34,11 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	"github.com/heroku/docker-registry-client/registry"
-	"github.com/mattn/go-colorable"
 
-	"github.com/aerokube/cm/render/rewriter"
 	"github.com/fatih/color"
-	. "github.com/fvbommel/sortorder"
 )
 
 const (
@@ -77,17 +75,30 @@ type DockerConfigurator struct {
 	UserNSAware
 	LogsAware
 	GracefulAware
+	InstanceAware
+	RetryAware
 	LastVersions int
-	Pull         bool
-	RegistryUrl  string
-	BrowsersJson string
-	ShmSize      int
-	Tmpfs        int
-	VNC          bool
-	docker       *client.Client
-	reg          *registry.Registry
-	authConfig   *configtypes.AuthConfig
-	registryHost string
+	// TagFilter limits browser image tags to matching ones
+	TagFilter          *regexp.Regexp
+	IncludePrereleases bool
+	Pull               bool
+	RegistryUrl        string
+	// RegistryMirrors are tried in order before RegistryUrl when fetching tags and pulling images
+	RegistryMirrors []string
+	BrowsersJson    string
+	Catalog         string
+	Parallel        int
+	PinDigests      bool
+	ShmSize         int
+	Tmpfs           int
+	VNC             bool
+	Volumes         []string
+	engine          *Engine
+	docker          *client.Client
+	registries      map[string]*registry.Registry
+	configFile      *configfile.ConfigFile
+	authConfig      *configtypes.AuthConfig
+	registryHost    string
 }
 
 func NewDockerConfigurator(config *LifecycleConfig) (*DockerConfigurator, error) {
@@ -104,18 +115,39 @@ func NewDockerConfigurator(config *LifecycleConfig) (*DockerConfigurator, error)
 		UserNSAware:            UserNSAware{UserNS: config.UserNS},
 		LogsAware:              LogsAware{DisableLogs: config.DisableLogs},
 		GracefulAware:          GracefulAware{Graceful: config.Graceful, GracefulTimeout: config.GracefulTimeout},
+		InstanceAware:          InstanceAware{Instance: config.Instance},
+		RetryAware:             RetryAware{Retry: config.Retry},
 		RegistryUrl:            config.RegistryUrl,
+		RegistryMirrors:        config.RegistryMirrors,
 		BrowsersJson:           config.BrowsersJson,
+		Catalog:                config.Catalog,
+		Parallel:               config.Parallel,
+		PinDigests:             config.PinDigests,
 		LastVersions:           config.LastVersions,
+		IncludePrereleases:     config.IncludePrereleases,
 		ShmSize:                config.ShmSize,
 		Tmpfs:                  config.Tmpfs,
 		VNC:                    config.VNC,
+		Volumes:                config.Volumes,
+		registries:             make(map[string]*registry.Registry),
+	}
+	if config.TagFilter != "" {
+		tagFilter, err := regexp.Compile(config.TagFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag filter: %v", err)
+		}
+		c.TagFilter = tagFilter
 	}
 	if c.Quiet {
 		log.SetFlags(0)
 		log.SetOutput(io.Discard)
 	}
-	err := c.initDockerClient()
+	engine, err := ResolveEngine(config.engineOptions())
+	if err != nil {
+		return nil, fmt.Errorf("new configurator: %v", err)
+	}
+	c.engine = engine
+	err = c.initDockerClient()
 	if err != nil {
 		return nil, fmt.Errorf("new configurator: %v", err)
 	}
@@ -128,31 +160,45 @@ func NewDockerConfigurator(config *LifecycleConfig) (*DockerConfigurator, error)
 	return c, nil
 }
 
-func createCompatibleDockerClient(onVersionSpecified, onVersionDetermined, onUsingDefaultVersion func(string)) (*client.Client, error) {
+// createCompatibleDockerClient negotiates API version with engine and falls back to probing versions one by one
+func createCompatibleDockerClient(engine *Engine, onVersionSpecified, onVersionDetermined, onUsingDefaultVersion func(string)) (*client.Client, error) {
+	opts, err := engine.clientOpts()
+	if err != nil {
+		return nil, err
+	}
 	dockerApiVersionEnv := os.Getenv(dockerApiVersion)
 	if dockerApiVersionEnv != "" {
 		onVersionSpecified(dockerApiVersionEnv)
-	} else {
-		maxMajorVersion, maxMinorVersion := parseVersion(api.DefaultVersion)
-		minMajorVersion, minMinorVersion := parseVersion("1.24")
-		for majorVersion := maxMajorVersion; majorVersion >= minMajorVersion; majorVersion-- {
-			for minorVersion := maxMinorVersion; minorVersion >= minMinorVersion; minorVersion-- {
-				apiVersion := fmt.Sprintf("%d.%d", majorVersion, minorVersion)
-				_ = os.Setenv(dockerApiVersion, apiVersion)
-				docker, err := client.NewClientWithOpts(client.FromEnv)
-				if err != nil {
-					return nil, err
-				}
-				if isDockerAPIVersionCorrect(docker) {
-					onVersionDetermined(apiVersion)
-					return docker, nil
-				}
-				_ = docker.Close()
+		return client.NewClientWithOpts(append(opts, client.WithVersion(dockerApiVersionEnv))...)
+	}
+	docker, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if ping, err := docker.Ping(context.Background()); err == nil && ping.APIVersion != "" {
+		docker.NegotiateAPIVersionPing(ping)
+		onVersionDetermined(docker.ClientVersion())
+		return docker, nil
+	}
+	_ = docker.Close()
+	maxMajorVersion, maxMinorVersion := parseVersion(api.DefaultVersion)
+	minMajorVersion, minMinorVersion := parseVersion("1.24")
+	for majorVersion := maxMajorVersion; majorVersion >= minMajorVersion; majorVersion-- {
+		for minorVersion := maxMinorVersion; minorVersion >= minMinorVersion; minorVersion-- {
+			apiVersion := fmt.Sprintf("%d.%d", majorVersion, minorVersion)
+			docker, err := client.NewClientWithOpts(append(opts, client.WithVersion(apiVersion))...)
+			if err != nil {
+				return nil, err
+			}
+			if isDockerAPIVersionCorrect(docker) {
+				onVersionDetermined(apiVersion)
+				return docker, nil
 			}
+			_ = docker.Close()
 		}
-		onUsingDefaultVersion(api.DefaultVersion)
 	}
-	return client.NewClientWithOpts(client.FromEnv)
+	onUsingDefaultVersion(api.DefaultVersion)
+	return client.NewClientWithOpts(append(opts, client.WithVersion(api.DefaultVersion))...)
 }
 
 func parseVersion(ver string) (int, int) {
@@ -180,6 +226,7 @@ func isDockerAPIVersionCorrect(docker *client.Client) bool {
 
 func (c *DockerConfigurator) initDockerClient() error {
 	docker, err := createCompatibleDockerClient(
+		c.engine,
 		func(specifiedApiVersion string) {
 			c.Pointf("Using Docker API version: %s", specifiedApiVersion)
 		},
@@ -194,58 +241,24 @@ func (c *DockerConfigurator) initDockerClient() error {
 		return fmt.Errorf("failed to init Docker client: %v", err)
 	}
 	c.docker = docker
+	detectEngineName(c.engine, docker)
 	return nil
 }
 
 func (c *DockerConfigurator) initAuthConfig() (*configtypes.AuthConfig, error) {
-	configFile, err := authconfig.Load("")
-	if err != nil {
-		return nil, err
-	}
 	u, err := url.Parse(c.RegistryUrl)
 	if err != nil {
 		return nil, err
 	}
-
-	registryHost := u.Host
 	if c.RegistryUrl != DefaultRegistryUrl {
-		c.registryHost = registryHost
-	}
-	if cfg, ok := configFile.AuthConfigs[registryHost]; ok {
-		c.Titlef(`Loaded authentication data for "%s"`, registryHost)
-		return &cfg, nil
+		c.registryHost = u.Host
 	}
-
-	return nil, nil
-}
-
-func (c *DockerConfigurator) getRegistryClient() *registry.Registry {
-	if c.reg != nil {
-		return c.reg
-	}
-
-	u := strings.TrimSuffix(c.RegistryUrl, "/")
-	username, password := "", ""
-	if c.authConfig != nil {
-		username, password = c.authConfig.Username, c.authConfig.Password
-	}
-	reg := &registry.Registry{
-		URL: u,
-		Client: &http.Client{
-			Transport: registry.WrapTransport(http.DefaultTransport, u, username, password),
-		},
-		Logf: func(format string, args ...interface{}) {
-			c.Tracef(format, args...)
-		},
-	}
-
-	if err := reg.Ping(); err != nil {
-		c.Errorf("Docker Registry is not available: %v", err)
-		return nil
+	configFile, err := authconfig.Load("")
+	if err != nil {
+		return nil, err
 	}
-
-	c.reg = reg
-	return reg
+	c.configFile = configFile
+	return c.registryAuthConfig(c.RegistryUrl), nil
 }
 
 func (c *DockerConfigurator) Close() error {
@@ -255,40 +268,63 @@ func (c *DockerConfigurator) Close() error {
 	return nil
 }
 
-func (c *DockerConfigurator) Status() {
-	selenoidImage := c.getSelenoidImage()
-	if selenoidImage != nil {
-		c.Pointf("Using Selenoid image: %s (%s)", selenoidImage.RepoTags[0], selenoidImage.ID)
-	} else {
-		c.Pointf("Selenoid image is not present")
+func (c *DockerConfigurator) Status() *Status {
+	status := &Status{
+		Service:   "Selenoid",
+		Instance:  c.Instance,
+		Mode:      ModeDocker,
+		ConfigDir: c.ConfigDir,
 	}
+	img := c.getSelenoidImage()
+	c.fillImageStatus(status, img)
 	configPath := getSelenoidConfigPath(c.ConfigDir)
-	c.Pointf("Selenoid configuration directory is %s", c.ConfigDir)
 	if fileExists(configPath) {
-		c.Pointf("Selenoid configuration file is %s", configPath)
-	} else {
-		c.Pointf("Selenoid is not configured")
+		status.ConfigPath = configPath
+		status.Browsers = readBrowsersStatus(configPath)
 	}
-	selenoidContainer := c.getSelenoidContainer()
-	if selenoidContainer != nil {
-		c.Pointf("Selenoid container is running: %s (%s)", selenoidContainerName, selenoidContainer.ID)
-	} else {
-		c.Pointf("Selenoid container is not running")
+	ctr := c.getSelenoidContainer()
+	c.fillContainerStatus(status, ctr, DefaultPort)
+	status.setState(ctr != nil, img != nil && status.ConfigPath != "")
+	return status
+}
+
+func (c *DockerConfigurator) UIStatus() *Status {
+	status := &Status{
+		Service:  "Selenoid UI",
+		Instance: c.Instance,
+		Mode:     ModeDocker,
+	}
+	img := c.getSelenoidUIImage()
+	c.fillImageStatus(status, img)
+	ctr := c.getSelenoidUIContainer()
+	c.fillContainerStatus(status, ctr, UIDefaultPort)
+	status.setState(ctr != nil, img != nil)
+	return status
+}
+
+func (c *DockerConfigurator) fillImageStatus(status *Status, img *image.Summary) {
+	if img != nil {
+		status.Image = &ImageInfo{Tag: img.RepoTags[0], ID: img.ID}
 	}
 }
 
-func (c *DockerConfigurator) UIStatus() {
-	selenoidUIImage := c.getSelenoidUIImage()
-	if selenoidUIImage != nil {
-		c.Pointf("Using Selenoid UI image: %s (%s)", selenoidUIImage.RepoTags[0], selenoidUIImage.ID)
-	} else {
-		c.Pointf("Selenoid UI image is not present")
+func (c *DockerConfigurator) fillContainerStatus(status *Status, ctr *types.Container, servicePort int) {
+	if ctr == nil {
+		return
 	}
-	selenoidUIContainer := c.getSelenoidUIContainer()
-	if selenoidUIContainer != nil {
-		c.Pointf("Selenoid UI container is running: %s (%s)", selenoidUIContainerName, selenoidUIContainer.ID)
-	} else {
-		c.Pointf("Selenoid UI container is not running")
+	status.ContainerID = ctr.ID
+	for _, p := range ctr.Ports {
+		if p.PublicPort != 0 && (status.Port == 0 || int(p.PrivatePort) == servicePort) {
+			status.Port = int(p.PublicPort)
+		}
+	}
+	info, err := c.docker.ContainerInspect(context.Background(), ctr.ID)
+	if err != nil {
+		return
+	}
+	startedAt, err := time.Parse(time.RFC3339Nano, info.State.StartedAt)
+	if err == nil {
+		status.setStartedAt(startedAt)
 	}
 }
 
@@ -356,20 +392,97 @@ func (c *DockerConfigurator) downloadImpl(imageName string, version string, erro
 	if version != Latest {
 		ref = imageWithTag(ref, version)
 	}
-	if !c.pullImage(context.Background(), ref) {
-		return "", errors.New(errorMessage)
+	err := c.pullImage(context.Background(), ref)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", errorMessage, err)
 	}
 	return ref, nil
 }
 
 func (c *DockerConfigurator) getLatestImageVersion(imageName string) *string {
-	tags := c.fetchImageTags(imageName)
+	// Prerelease flag is for browser tags, Selenoid and Selenoid UI are always taken from releases
+	tags := sortedTags(c.fetchRawImageTags(imageName), false)
 	if len(tags) > 0 {
 		return &tags[0]
 	}
 	return nil
 }
 
+func (c *DockerConfigurator) InstalledVersion() string {
+	return c.installedImageVersion(c.getSelenoidContainer(), selenoidImage)
+}
+
+func (c *DockerConfigurator) InstalledUIVersion() string {
+	return c.installedImageVersion(c.getSelenoidUIContainer(), selenoidUIImage)
+}
+
+// installedImageVersion returns image tag of running container or of the newest downloaded image
+func (c *DockerConfigurator) installedImageVersion(ctr *types.Container, imageName string) string {
+	ref := ""
+	if ctr != nil {
+		ref = ctr.Image
+	} else if img := c.getImage(imageName, ""); img != nil && len(img.RepoTags) > 0 {
+		ref = img.RepoTags[0]
+	}
+	if ref == "" {
+		return ""
+	}
+	if strings.HasPrefix(ref, "sha256:") {
+		return unknownVersion
+	}
+	if repo := repositoryName(ref); repo != ref && !strings.Contains(ref, digestSeparator) {
+		return strings.TrimPrefix(ref, repo+":")
+	}
+	return Latest
+}
+
+func (c *DockerConfigurator) LatestVersion() (string, error) {
+	return c.latestImageVersion(selenoidImage)
+}
+
+func (c *DockerConfigurator) LatestUIVersion() (string, error) {
+	return c.latestImageVersion(selenoidUIImage)
+}
+
+func (c *DockerConfigurator) latestImageVersion(imageName string) (string, error) {
+	if c.Version != Latest {
+		return c.Version, nil
+	}
+	latestVersion := c.getLatestImageVersion(imageName)
+	if latestVersion == nil {
+		return "", fmt.Errorf("failed to determine latest version of %s", imageName)
+	}
+	return *latestVersion, nil
+}
+
+// AvailableBrowsers returns browser versions that Configure would add without pulling images
+func (c *DockerConfigurator) AvailableBrowsers() (map[string][]string, error) {
+	var cfg SelenoidConfig
+	if c.BrowsersJson != "" {
+		data, err := os.ReadFile(c.BrowsersJson)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read browsers.json from %s: %v", c.BrowsersJson, err)
+		}
+		err = json.Unmarshal(data, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse browsers.json from %s: %v", c.BrowsersJson, err)
+		}
+	} else {
+		catalog, err := c.loadCatalog()
+		if err != nil {
+			return nil, err
+		}
+		cfg = c.createConfig(catalog, false)
+	}
+	ret := make(map[string][]string)
+	for name, versions := range cfg {
+		for version := range versions.Versions {
+			ret[name] = append(ret[name], version)
+		}
+	}
+	return ret, nil
+}
+
 func (c *DockerConfigurator) IsConfigured() bool {
 	return fileExists(getSelenoidConfigPath(c.ConfigDir))
 }
@@ -383,7 +496,11 @@ func (c *DockerConfigurator) Configure() (*SelenoidConfig, error) {
 		return c.syncWithConfig()
 	}
 
-	cfg := c.createConfig()
+	catalog, err := c.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	cfg := c.createConfig(catalog, c.DownloadNeeded)
 	data, err := json.MarshalIndent(cfg, "", "    ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal json: %v", err)
@@ -403,47 +520,86 @@ func (c *DockerConfigurator) syncWithConfig() (*SelenoidConfig, error) {
 		return nil, fmt.Errorf("failed to parse browsers.json from %s: %v", c.BrowsersJson, err)
 	}
 	if c.DownloadNeeded {
+		var refs []string
 		for _, versions := range cfg {
 			for _, version := range versions.Versions {
 				if ref, ok := version.Image.(string); ok {
-					ctx := context.Background()
-					if !c.pullImage(ctx, ref) {
-						return nil, fmt.Errorf("failed to pull image %s from browsers.json file %s", ref, c.BrowsersJson)
-					}
+					refs = append(refs, ref)
 				} else {
 					c.Pointf("Skipping non-Docker image specification: %v", version.Image)
 				}
 			}
 		}
-		c.pullVideoRecorderImage()
+		sort.Strings(refs)
+		failed := c.pullImages(context.Background(), append(refs, c.getFullyQualifiedImageRef(videoRecorderImage)))
+		for _, ref := range refs {
+			if err, ok := failed[ref]; ok {
+				return nil, fmt.Errorf("failed to pull image %s from browsers.json file %s: %v", ref, c.BrowsersJson, err)
+			}
+		}
+	}
+	if c.PinDigests {
+		for _, versions := range cfg {
+			c.pinDigests(versions)
+		}
+		data, err = json.MarshalIndent(cfg, "", "    ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal json: %v", err)
+		}
 	}
 	return &cfg, os.WriteFile(getSelenoidConfigPath(c.ConfigDir), data, 0644)
 }
 
-func (c *DockerConfigurator) createConfig() SelenoidConfig {
+func (c *DockerConfigurator) loadCatalog() (Catalog, error) {
+	if c.Catalog == "" {
+		return DefaultCatalog(), nil
+	}
+	c.Titlef(`Loading browsers catalog from "%v"...`, color.GreenString(c.Catalog))
+	return LoadCatalog(c.Catalog, c.Retry)
+}
+
+func (c *DockerConfigurator) createConfig(catalog Catalog, pull bool) SelenoidConfig {
 	requestedBrowsers := parseRequestedBrowsers(&c.Logger, c.Browsers)
-	browsersToIterate := c.getBrowsersToIterate(requestedBrowsers)
-	browsers := make(map[string]config.Versions)
-	for browserName, img := range browsersToIterate {
+	browsersToIterate := c.getBrowsersToIterate(catalog, requestedBrowsers)
+	browserNames := Catalog(browsersToIterate).Names()
+	browserTags := make(map[string][]string)
+	var refs []string
+	for _, browserName := range browserNames {
 		c.Titlef(`Processing browser "%v"...`, color.GreenString(browserName))
-		tags := c.fetchImageTags(img)
+		img := browsersToIterate[browserName].Image
+		tags := c.fetchRawImageTags(img)
 		if c.VNC {
 			c.Pointf("Requested to download VNC images but this feature is now deprecated as all images contain VNC.")
 		}
 		versionConstraint := requestedBrowsers[browserName]
-		pulledTags := c.filterTags(tags, versionConstraint)
+		browserTags[browserName] = c.filterTags(tags, versionConstraint)
 		fullyQualifiedImage := c.getFullyQualifiedImageRef(img)
-		if c.DownloadNeeded {
-			pulledTags = c.pullImages(fullyQualifiedImage, pulledTags)
+		for _, tag := range browserTags[browserName] {
+			refs = append(refs, imageWithTag(fullyQualifiedImage, tag))
+		}
+	}
+	failed := make(map[string]error)
+	if pull {
+		c.Titlef("Pulling images...")
+		failed = c.pullImages(context.Background(), append(refs, c.getFullyQualifiedImageRef(videoRecorderImage)))
+	}
+	browsers := make(map[string]config.Versions)
+	for _, browserName := range browserNames {
+		fullyQualifiedImage := c.getFullyQualifiedImageRef(browsersToIterate[browserName].Image)
+		var pulledTags []string
+		for _, tag := range browserTags[browserName] {
+			if _, ok := failed[imageWithTag(fullyQualifiedImage, tag)]; !ok {
+				pulledTags = append(pulledTags, tag)
+			}
 		}
-
 		if len(pulledTags) > 0 {
-			browsers[browserName] = c.createVersions(browserName, fullyQualifiedImage, pulledTags)
+			versions := c.createVersions(browsersToIterate[browserName], fullyQualifiedImage, pulledTags)
+			if c.PinDigests {
+				c.pinDigests(versions)
+			}
+			browsers[browserName] = versions
 		}
 	}
-	if c.DownloadNeeded {
-		c.pullVideoRecorderImage()
-	}
 	return browsers
 }
 
@@ -473,49 +629,55 @@ func parseRequestedBrowsers(logger *Logger, requestedBrowsers string) map[string
 	return ret
 }
 
-func (c *DockerConfigurator) getBrowsersToIterate(requestedBrowsers map[string][]*semver.Constraints) map[string]string {
-	defaultBrowsers := map[string]string{
-		"firefox": "selenoid/firefox",
-		"chrome":  "selenoid/chrome",
-		"opera":   "selenoid/opera",
-	}
+func (c *DockerConfigurator) getBrowsersToIterate(catalog Catalog, requestedBrowsers map[string][]*semver.Constraints) map[string]*CatalogBrowser {
+	ret := make(map[string]*CatalogBrowser)
 	if len(requestedBrowsers) > 0 {
-		if _, ok := requestedBrowsers[android]; ok {
-			defaultBrowsers[android] = "selenoid/android"
-		}
-		if _, ok := requestedBrowsers[edge]; ok {
-			defaultBrowsers[edge] = "browsers/edge"
-		}
-		ret := make(map[string]string)
 		for browserName := range requestedBrowsers {
-			if img, ok := defaultBrowsers[browserName]; ok {
-				ret[browserName] = img
+			if browser, ok := catalog[browserName]; ok {
+				ret[browserName] = browser
 				continue
 			}
 			c.Errorf("Unsupported browser: %s", browserName)
 		}
-
 		return ret
 	}
-	return defaultBrowsers
+	for browserName, browser := range catalog {
+		if browser.Default {
+			ret[browserName] = browser
+		}
+	}
+	return ret
 }
 
+// fetchImageTags returns image release tags from newest to oldest
 func (c *DockerConfigurator) fetchImageTags(image string) []string {
+	return sortedTags(c.fetchRawImageTags(image), c.IncludePrereleases)
+}
+
+// fetchRawImageTags returns image tags as listed by the first available mirror or registry
+func (c *DockerConfigurator) fetchRawImageTags(image string) []string {
 	c.Pointf(`Fetching tags for image %v`, color.BlueString(image))
-	reg := c.getRegistryClient()
-	if reg == nil {
-		c.Errorf(`Docker registry client not initialized`)
-		return nil
+	for _, mirror := range c.RegistryMirrors {
+		tags, err := c.fetchRegistryTags(mirror, c.mirrorRepository(image))
+		if err == nil {
+			return tags
+		}
+		c.Errorf(`Failed to fetch tags for image "%s" from mirror %s: %v`, image, mirror, err)
 	}
-	tags, err := reg.Tags(image)
+	tags, err := c.fetchRegistryTags(c.RegistryUrl, image)
 	if err != nil {
 		c.Errorf(`Failed to fetch tags for image "%s": %v`, image, err)
 		return nil
 	}
-	tagsWithoutLatest := filterOutLatest(tags)
-	strSlice := Natural(tagsWithoutLatest)
-	sort.Sort(sort.Reverse(strSlice))
-	return tagsWithoutLatest
+	return tags
+}
+
+func (c *DockerConfigurator) fetchRegistryTags(registryUrl string, image string) ([]string, error) {
+	reg, err := c.getRegistryClient(registryUrl)
+	if err != nil {
+		return nil, err
+	}
+	return c.registryTags(reg, image)
 }
 
 func filterOutLatest(tags []string) []string {
@@ -528,7 +690,29 @@ func filterOutLatest(tags []string) []string {
 	return ret
 }
 
+// matchingTags applies tag filter to raw tags before sorting, so that it can select tags skipped by default.
+// Matching tags not following semantic versioning are kept in original order after sorted versions.
+func (c *DockerConfigurator) matchingTags(tags []string) []string {
+	if c.TagFilter == nil {
+		return sortedTags(tags, c.IncludePrereleases)
+	}
+	var versions, other []string
+	for _, tag := range filterOutLatest(tags) {
+		if !c.TagFilter.MatchString(tag) {
+			continue
+		}
+		if _, err := semver.NewVersion(tag); err != nil {
+			other = append(other, tag)
+			continue
+		}
+		versions = append(versions, tag)
+	}
+	return append(sortedTags(versions, c.IncludePrereleases), other...)
+}
+
+// filterTags selects requested versions from raw image tags
 func (c *DockerConfigurator) filterTags(tags []string, versionConstraints []*semver.Constraints) []string {
+	tags = c.matchingTags(tags)
 	if len(versionConstraints) > 0 {
 		var ret []string
 		for _, tag := range tags {
@@ -544,13 +728,13 @@ func (c *DockerConfigurator) filterTags(tags []string, versionConstraints []*sem
 			}
 		}
 		return ret
-	} else if c.LastVersions > 0 && c.LastVersions <= len(tags) {
-		return tags[:c.LastVersions]
+	} else if c.LastVersions > 0 {
+		return lastVersions(tags, c.LastVersions)
 	}
 	return tags
 }
 
-func (c *DockerConfigurator) createVersions(browserName string, image string, tags []string) config.Versions {
+func (c *DockerConfigurator) createVersions(catalogBrowser *CatalogBrowser, image string, tags []string) config.Versions {
 	versions := config.Versions{
 		Default:  tags[0],
 		Versions: make(map[string]*config.Browser),
@@ -559,21 +743,23 @@ func (c *DockerConfigurator) createVersions(browserName string, image string, ta
 		version := tag
 		browser := &config.Browser{
 			Image: imageWithTag(image, tag),
-			Port:  "4444",
-			Path:  "/",
+			Port:  catalogBrowser.port(),
+			Path:  catalogBrowser.path(version),
 		}
-		if browserName == firefox || browserName == android || (browserName == opera && version == tag_1216) {
-			browser.Path = "/wd/hub"
+		tmpfs := make(map[string]string)
+		for k, v := range catalogBrowser.Tmpfs {
+			tmpfs[k] = v
 		}
 		if c.Tmpfs > 0 {
-			tmpfs := make(map[string]string)
 			tmpfs["/tmp"] = fmt.Sprintf("size=%dm", c.Tmpfs)
+		}
+		if len(tmpfs) > 0 {
 			browser.Tmpfs = tmpfs
 		}
 		if c.ShmSize > 0 {
 			browser.ShmSize, _ = units.RAMInBytes(fmt.Sprintf("%dm", c.ShmSize))
 		}
-		browserEnv := strings.Fields(c.BrowserEnv)
+		browserEnv := append(append([]string{}, catalogBrowser.Env...), c.BrowserEnv...)
 		if len(browserEnv) > 0 {
 			browser.Env = browserEnv
 		}
@@ -586,37 +772,27 @@ func imageWithTag(image string, tag string) string {
 	return fmt.Sprintf("%s:%s", image, tag)
 }
 
-func (c *DockerConfigurator) pullImages(image string, tags []string) []string {
-	var pulledTags []string
-	ctx := context.Background()
-	for _, tag := range tags {
-		ref := imageWithTag(image, tag)
-		if !c.pullImage(ctx, ref) {
-			continue
-		}
-		pulledTags = append(pulledTags, tag)
-	}
-	return pulledTags
-}
-
-func (c *DockerConfigurator) pullVideoRecorderImage() {
-	c.Titlef("Pulling video recorder image...")
-	c.pullImage(context.Background(), c.getFullyQualifiedImageRef(videoRecorderImage))
-}
-
 func (c *DockerConfigurator) getFullyQualifiedImageRef(ref string) string {
-	if c.registryHost != "" {
+	if c.registryHost != "" && !hasRegistryHost(ref) {
 		return fmt.Sprintf("%s/%s", c.registryHost, ref)
 	}
 	return ref
 }
 
+// hasRegistryHost checks whether image reference starts with registry host like Docker does, e.g. example.com/chrome or localhost:5000/chrome
+func hasRegistryHost(ref string) bool {
+	host, _, ok := strings.Cut(ref, "/")
+	return ok && (strings.ContainsAny(host, ".:") || host == "localhost")
+}
+
 // JSONMessage defines a message struct from docker.
 type JSONMessage struct {
 	Status          string        `json:"status,omitempty"`
 	Progress        *JSONProgress `json:"progressDetail,omitempty"`
 	ID              string        `json:"id,omitempty"`
 	ProgressMessage string        `json:"progress,omitempty"` //deprecated
+	Stream          string        `json:"stream,omitempty"`
+	Error           string        `json:"error,omitempty"`
 }
 
 // JSONProgress describes a Progress. terminalFd is the fd of the current terminal,
@@ -633,60 +809,158 @@ type JSONProgress struct {
 	Units      string `json:"units,omitempty"`
 }
 
-func (c *DockerConfigurator) pullImage(ctx context.Context, ref string) bool {
-	c.Pointf("Pulling image %v", color.BlueString(ref))
-	pullOptions := image.PullOptions{}
-	if c.authConfig != nil {
-		buf, err := json.Marshal(c.authConfig)
-		if err != nil {
-			c.Errorf("Failed to prepare registry authentication config: %v", err)
-		} else {
-			pullOptions.RegistryAuth = base64.URLEncoding.EncodeToString(buf)
+func (c *DockerConfigurator) pullImage(ctx context.Context, ref string) error {
+	return c.pullImages(ctx, []string{ref})[ref]
+}
+
+// pullImages pulls images concurrently and returns errors for images that were not pulled
+func (c *DockerConfigurator) pullImages(ctx context.Context, refs []string) map[string]error {
+	refs = uniqueStrings(refs)
+	parallel := c.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	if len(refs) == 1 {
+		c.Pointf("Pulling image %v", color.BlueString(refs[0]))
+	} else {
+		c.Pointf("Pulling %d images, %d at a time", len(refs), parallel)
+	}
+	progress := newPullProgress(c.Quiet, refs)
+	failed := make(map[string]error)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, parallel)
+	for _, ref := range refs {
+		wg.Add(1)
+		go func(ref string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			progress.start(ref)
+			err := c.pullImageWithProgress(ctx, ref, progress)
+			progress.finish(ref, err)
+			if err != nil {
+				lock.Lock()
+				failed[ref] = err
+				lock.Unlock()
+			}
+		}(ref)
+	}
+	wg.Wait()
+	if len(failed) > 0 {
+		c.Errorf("Failed to pull %d of %d images:", len(failed), len(refs))
+		for _, ref := range refs {
+			if err, ok := failed[ref]; ok {
+				c.Errorf("\t%s: %v", ref, color.RedString("%v", err))
+			}
+		}
+	}
+	return failed
+}
+
+// pullImageWithProgress tries every mirror once and then pulls from registry with retries.
+// Image pulled from mirror is tagged with its original reference.
+func (c *DockerConfigurator) pullImageWithProgress(ctx context.Context, ref string, progress *pullProgress) error {
+	for _, mirror := range c.RegistryMirrors {
+		mirrorRef := c.mirrorImageRef(mirror, ref)
+		err := c.pullImageOnce(ctx, mirrorRef, ref, c.pullOptions(c.registryAuthConfig(mirror)), progress)
+		if err == nil {
+			err = c.docker.ImageTag(ctx, mirrorRef, ref)
+		}
+		if err == nil {
+			// Only original reference is kept, so that mirror tags do not pile up in local storage
+			_, err = c.docker.ImageRemove(ctx, mirrorRef, image.RemoveOptions{PruneChildren: false})
+			if err != nil {
+				c.Tracef("Failed to remove image tag %s: %v", mirrorRef, err)
+			}
+			return nil
 		}
+		c.Tracef("Failed to pull image %s from mirror %s: %v", ref, mirror, err)
 	}
+	pullOptions := c.pullOptions(c.authConfig)
+	return c.Retry.do(ctx, &c.Logger, func() error {
+		return c.pullImageOnce(ctx, ref, ref, pullOptions, progress)
+	})
+}
+
+// pullImageOnce pulls image reference and reports progress for the requested reference it was pulled for
+func (c *DockerConfigurator) pullImageOnce(ctx context.Context, ref string, progressRef string, pullOptions image.PullOptions, progress *pullProgress) error {
 	resp, err := c.docker.ImagePull(ctx, ref, pullOptions)
 	if err != nil {
-		c.Errorf(`Failed to pull image "%s": %v`, ref, err)
-		return false
+		return err
 	}
 	defer resp.Close()
 
-	var row JSONMessage
-
 	scanner := bufio.NewScanner(resp)
-	writer := rewriter.New(colorable.NewColorableStdout())
-
-	for _ = ""; scanner.Scan(); {
+	for scanner.Scan() {
+		var row JSONMessage
 		err := json.Unmarshal(scanner.Bytes(), &row)
 		if err != nil {
-			return false
+			return fmt.Errorf("invalid pull progress message: %v", err)
+		}
+		if row.Error != "" {
+			return errors.New(row.Error)
 		}
-
 		select {
 		case <-ctx.Done():
-			{
-				c.Errorf(`Pulling "%s" interrupted: %v`, ref, ctx.Err())
-				return false
-			}
+			return fmt.Errorf("interrupted: %v", ctx.Err())
 		default:
-			{
-				if row.Progress != nil {
-					if row.Progress.Current != row.Progress.Total {
-						_, _ = fmt.Fprintf(writer, "\t[%s]: %s %s\n", row.ID, row.Status, row.ProgressMessage)
-					} else {
-						_, _ = fmt.Fprint(writer, "\r")
-					}
-				}
+			progress.update(progressRef, &row)
+		}
+	}
+	return scanner.Err()
+}
 
-				_ = writer.Flush()
-			}
+func (c *DockerConfigurator) VideoRecorderImage() string {
+	return c.getFullyQualifiedImageRef(videoRecorderImage)
+}
+
+func (c *DockerConfigurator) SaveImages(w io.Writer, refs []string) error {
+	c.Pointf("Saving %d images", len(refs))
+	resp, err := c.docker.ImageSave(context.Background(), refs)
+	if err != nil {
+		return fmt.Errorf("failed to save images: %v", err)
+	}
+	defer resp.Close()
+	_, err = io.Copy(w, resp)
+	if err != nil {
+		return fmt.Errorf("failed to save images: %v", err)
+	}
+	return nil
+}
+
+func (c *DockerConfigurator) LoadImages(r io.Reader) error {
+	resp, err := c.docker.ImageLoad(context.Background(), r, true)
+	if err != nil {
+		return fmt.Errorf("failed to load images: %v", err)
+	}
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var row JSONMessage
+		if json.Unmarshal(scanner.Bytes(), &row) != nil {
+			continue
+		}
+		if row.Error != "" {
+			return fmt.Errorf("failed to load images: %s", row.Error)
+		}
+		if stream := strings.TrimSpace(row.Stream); stream != "" {
+			c.Pointf("%s", stream)
 		}
 	}
+	return scanner.Err()
+}
 
-	if err := scanner.Err(); err != nil {
-		c.Errorf(`Failed to pull image "%s": %v`, ref, color.RedString("%v", err))
+func uniqueStrings(values []string) []string {
+	var ret []string
+	seen := make(map[string]struct{})
+	for _, v := range values {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			ret = append(ret, v)
+		}
 	}
-	return true
+	return ret
 }
 
 func (c *DockerConfigurator) IsRunning() bool {
@@ -694,7 +968,7 @@ func (c *DockerConfigurator) IsRunning() bool {
 }
 
 func (c *DockerConfigurator) getSelenoidContainer() *types.Container {
-	return c.getContainer(selenoidContainerName)
+	return c.getContainer(c.instanceName(selenoidContainerName))
 }
 
 func (c *DockerConfigurator) IsUIRunning() bool {
@@ -702,7 +976,7 @@ func (c *DockerConfigurator) IsUIRunning() bool {
 }
 
 func (c *DockerConfigurator) getSelenoidUIContainer() *types.Container {
-	return c.getContainer(selenoidUIContainerName)
+	return c.getContainer(c.instanceName(selenoidUIContainerName))
 }
 
 func (c *DockerConfigurator) getContainer(name string) *types.Container {
@@ -737,27 +1011,35 @@ const (
 	networkName  = "selenoid"
 )
 
+// dockerSocket is a variable to be overridden in tests
+var dockerSocket = "/var/run/docker.sock"
+
 func (c *DockerConfigurator) Start() error {
 	img := c.getSelenoidImage()
 	if img == nil {
 		return errors.New("selenoid image is not downloaded: this is probably a bug")
 	}
+	return c.startContainer(c.selenoidContainerConfig(img))
+}
 
-	volumeConfigDir := getVolumeConfigDir(c.ConfigDir, selenoidConfigDirElem)
-	videoConfigDir := getVolumeConfigDir(filepath.Join(c.ConfigDir, videoDirName), append(selenoidConfigDirElem, videoDirName))
-	logsConfigDir := getVolumeConfigDir(filepath.Join(c.ConfigDir, logsDirName), append(selenoidConfigDirElem, logsDirName))
+func (c *DockerConfigurator) selenoidContainerConfig(img *image.Summary) *containerConfig {
+
+	configDirElem := getInstanceConfigDirElem(c.Instance, selenoidConfigDirElem)
+	volumeConfigDir := getVolumeConfigDir(c.ConfigDir, configDirElem)
+	videoConfigDir := getVolumeConfigDir(filepath.Join(c.ConfigDir, videoDirName), withElem(configDirElem, videoDirName))
+	logsConfigDir := getVolumeConfigDir(filepath.Join(c.ConfigDir, logsDirName), withElem(configDirElem, logsDirName))
 	volumes := []string{
 		fmt.Sprintf("%s:/etc/selenoid:ro,Z", volumeConfigDir),
 		fmt.Sprintf("%s:/opt/selenoid/video:Z", videoConfigDir),
 		fmt.Sprintf("%s:/opt/selenoid/logs:Z", logsConfigDir),
 	}
-	const dockerSocket = "/var/run/docker.sock"
 	if isWindows() {
 		//With two slashes. See https://stackoverflow.com/questions/36765138/bind-to-docker-socket-on-windows
 		volumes = append(volumes, fmt.Sprintf("/%s:%s", dockerSocket, dockerSocket))
-	} else if fileExists(dockerSocket) {
-		volumes = append(volumes, fmt.Sprintf("%s:%s:Z", dockerSocket, dockerSocket))
+	} else if socketVolume := c.engine.socketVolume(); socketVolume != "" {
+		volumes = append(volumes, socketVolume)
 	}
+	volumes = append(volumes, c.Volumes...)
 
 	cmd := []string{}
 	overrideCmd := strings.Fields(c.Args)
@@ -777,25 +1059,25 @@ func (c *DockerConfigurator) Start() error {
 		cmd = append(cmd, "-log-output-dir", "/opt/selenoid/logs/")
 	}
 	if !contains(cmd, "-container-network") {
-		cmd = append(cmd, "-container-network", networkName)
+		cmd = append(cmd, "-container-network", c.instanceName(networkName))
 	}
 
-	overrideEnv := strings.Fields(c.Env)
-	if !strings.Contains(c.Env, "OVERRIDE_VIDEO_OUTPUT_DIR") {
+	overrideEnv := append([]string{}, c.Env...)
+	if !containsEnv(c.Env, "OVERRIDE_VIDEO_OUTPUT_DIR") {
 		overrideEnv = append(overrideEnv, fmt.Sprintf("OVERRIDE_VIDEO_OUTPUT_DIR=%s", videoConfigDir))
 	}
-	cfg := &containerConfig{
-		Name:        selenoidContainerName,
+	return &containerConfig{
+		Name:        c.instanceName(selenoidContainerName),
 		Image:       img,
 		HostPort:    c.Port,
 		ServicePort: DefaultPort,
 		Volumes:     volumes,
-		Network:     networkName,
+		Network:     c.instanceName(networkName),
 		Cmd:         cmd,
 		OverrideEnv: overrideEnv,
+		SecurityOpt: c.engine.securityOpt(),
 		UserNS:      c.UserNS,
 	}
-	return c.startContainer(cfg)
 }
 
 func isVideoRecordingSupported(logger Logger, version string) bool {
@@ -873,23 +1155,31 @@ func (c *DockerConfigurator) StartUI() error {
 	if img == nil {
 		return errors.New("selenoid ui image is not downloaded: this is probably a bug")
 	}
-
-	var cmd, candidates []string
 	var selenoidUri string
+	var candidates []string
+	// Selenoid UI connects to container port in the same network, Ggr UI is not managed by cm and is shared by all instances
 containers:
 	for _, containerName := range []string{
-		selenoidContainerName, ggrUIContainerName,
+		c.instanceName(selenoidContainerName), ggrUIContainerName,
 	} {
 		if ctr := c.getContainer(containerName); ctr != nil {
 			for _, p := range ctr.Ports {
 				if p.PublicPort != 0 {
-					selenoidUri = fmt.Sprintf("--selenoid-uri=http://%s:%d", containerName, p.PublicPort)
+					selenoidUri = fmt.Sprintf("--selenoid-uri=http://%s:%d", containerName, p.PrivatePort)
 					candidates = []string{containerName}
 					break containers
 				}
 			}
 		}
 	}
+	if len(candidates) == 0 {
+		c.Errorf("Neither Selenoid nor Ggr UI is started. Selenoid UI may not work.")
+	}
+	return c.startContainer(c.selenoidUIContainerConfig(img, selenoidUri))
+}
+
+func (c *DockerConfigurator) selenoidUIContainerConfig(img *image.Summary, selenoidUri string) *containerConfig {
+	var cmd []string
 	overrideCmd := strings.Fields(c.Args)
 	if len(overrideCmd) > 0 {
 		cmd = overrideCmd
@@ -897,23 +1187,17 @@ containers:
 	if !contains(cmd, "--selenoid-uri") {
 		cmd = append(cmd, selenoidUri)
 	}
-
-	if len(candidates) == 0 {
-		c.Errorf("Neither Selenoid nor Ggr UI is started. Selenoid UI may not work.")
-	}
-
-	overrideEnv := strings.Fields(c.Env)
-	cfg := &containerConfig{
-		Name:        selenoidUIContainerName,
+	overrideEnv := append([]string{}, c.Env...)
+	return &containerConfig{
+		Name:        c.instanceName(selenoidUIContainerName),
 		Image:       img,
 		HostPort:    c.Port,
 		ServicePort: UIDefaultPort,
-		Network:     networkName,
+		Network:     c.instanceName(networkName),
 		Cmd:         cmd,
 		OverrideEnv: overrideEnv,
 		UserNS:      c.UserNS,
 	}
-	return c.startContainer(cfg)
 }
 
 func validateEnviron(envs []string) []string {
@@ -927,6 +1211,15 @@ func validateEnviron(envs []string) []string {
 	return validEnv
 }
 
+func containsEnv(envs []string, name string) bool {
+	for _, e := range envs {
+		if strings.HasPrefix(e, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
 type containerConfig struct {
 	Name        string
 	Image       *image.Summary
@@ -937,6 +1230,7 @@ type containerConfig struct {
 	Cmd         []string
 	OverrideEnv []string
 	UserNS      string
+	SecurityOpt []string
 	PrintLogs   bool
 }
 
@@ -973,14 +1267,14 @@ func (c *DockerConfigurator) startContainer(cfg *containerConfig) error {
 	}
 	hostConfig := container.HostConfig{
 		Binds:       cfg.Volumes,
-		NetworkMode: networkName,
+		NetworkMode: container.NetworkMode(cfg.Network),
+		SecurityOpt: cfg.SecurityOpt,
 	}
 	if cfg.UserNS != "" {
-		mode := container.UsernsMode(cfg.UserNS)
-		if !mode.Valid() {
-			return fmt.Errorf("invalid userns value: %s", cfg.UserNS)
+		if !c.engine.validUserNS(cfg.UserNS) {
+			return fmt.Errorf("invalid userns value for %s: %s", c.engine.Title(), cfg.UserNS)
 		}
-		hostConfig.UsernsMode = mode
+		hostConfig.UsernsMode = container.UsernsMode(cfg.UserNS)
 	}
 	if cfg.PrintLogs {
 		containerConfig.Tty = true
@@ -1058,6 +1352,19 @@ func (c *DockerConfigurator) Stop() error {
 	return nil
 }
 
+// Reload sends SIGHUP to Selenoid container to apply new browsers.json without dropping sessions
+func (c *DockerConfigurator) Reload() error {
+	sc := c.getSelenoidContainer()
+	if sc == nil {
+		return errors.New("selenoid container is not running")
+	}
+	err := c.docker.ContainerKill(context.Background(), sc.ID, "HUP")
+	if err != nil {
+		return fmt.Errorf("failed to send signal to Selenoid container: %v", err)
+	}
+	return nil
+}
+
 func (c *DockerConfigurator) StopUI() error {
 	uc := c.getSelenoidUIContainer()
 	if uc != nil {
@@ -1068,3 +1375,36 @@ func (c *DockerConfigurator) StopUI() error {
 	}
 	return nil
 }
+
+func (c *DockerConfigurator) Logs(w io.Writer, opts LogsOptions) error {
+	return c.containerLogs(c.instanceName(selenoidContainerName), w, opts)
+}
+
+func (c *DockerConfigurator) UILogs(w io.Writer, opts LogsOptions) error {
+	return c.containerLogs(c.instanceName(selenoidUIContainerName), w, opts)
+}
+
+func (c *DockerConfigurator) containerLogs(name string, w io.Writer, opts LogsOptions) error {
+	tail := "all"
+	if opts.Tail > 0 {
+		tail = strconv.Itoa(opts.Tail)
+	}
+	since := ""
+	if !opts.Since.IsZero() {
+		since = strconv.FormatInt(opts.Since.Unix(), 10)
+	}
+	r, err := c.docker.ContainerLogs(context.Background(), name, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       tail,
+		Follow:     opts.Follow,
+		Since:      since,
+		Timestamps: opts.Timestamps,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read container logs: %v", err)
+	}
+	defer r.Close()
+	_, err = stdcopy.StdCopy(w, w, r)
+	return err
+}
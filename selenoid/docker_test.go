@@ -1,16 +1,22 @@
 package selenoid
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/aerokube/selenoid/config"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/pkg/stdcopy"
 	assert "github.com/stretchr/testify/require"
 )
 
@@ -131,10 +137,45 @@ func mux() http.Handler {
 	mux.HandleFunc("/v1.29/images/create", http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
+			if strings.Contains(r.URL.Query().Get("fromImage"), "missing") {
+				_, _ = w.Write([]byte(`{"error": "manifest unknown"}`))
+				return
+			}
 			output := `{"id": "a86cd3433934", "status": "Downloading layer"}`
 			_, _ = w.Write([]byte(output))
 		},
 	))
+	mux.HandleFunc("/v1.29/images/get", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(strings.Join(r.URL.Query()["names"], "\n")))
+		},
+	))
+	mux.HandleFunc("/v1.29/images/load", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+			for _, ref := range strings.Split(string(data), "\n") {
+				_, _ = fmt.Fprintf(w, "{\"stream\": \"Loaded image: %s\\n\"}\n", ref)
+			}
+		},
+	))
+	mux.HandleFunc("/v1.29/images/", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			ref := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1.29/images/"), "/json")
+			if strings.Contains(ref, "missing") {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message": "No such image"}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			repo := repositoryName(ref)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Id":          "sha256:e90e34656806",
+				"RepoDigests": []string{"docker.io/other@sha256:0", repo + "@" + testDigest(repo)},
+			})
+		},
+	))
 	mux.HandleFunc("/v1.29/images/json", http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
@@ -222,6 +263,29 @@ func mux() http.Handler {
 			_, _ = w.Write([]byte("Some logs...\n"))
 		},
 	))
+	mux.HandleFunc("/v1.29/containers/selenoid/logs", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = stdcopy.NewStdWriter(w, stdcopy.Stdout).Write([]byte("Selenoid logs...\n"))
+		},
+	))
+	mux.HandleFunc("/v1.29/containers/e90e34656806/json", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			output := fmt.Sprintf(`
+			{
+				"Id": "e90e34656806",
+				"Name": "/%s",
+				"State": {
+					"Status": "running",
+					"Running": true,
+					"StartedAt": "2020-02-29T14:41:12.960257Z"
+				}
+			}
+			`, containerName)
+			_, _ = w.Write([]byte(output))
+		},
+	))
 	mux.HandleFunc("/v1.29/containers/e90e34656806", http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNoContent)
@@ -267,6 +331,40 @@ func mux() http.Handler {
 	return mux
 }
 
+// mockDockerServerWith serves requests accepted by handle and passes the rest to mux()
+func mockDockerServerWith(handle func(w http.ResponseWriter, r *http.Request) bool) *httptest.Server {
+	docker := mux()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !handle(w, r) {
+			docker.ServeHTTP(w, r)
+		}
+	}))
+}
+
+// requestRecorder collects values from concurrently served mock requests
+type requestRecorder struct {
+	lock   sync.Mutex
+	values []string
+}
+
+func (rr *requestRecorder) record(value string) {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	rr.values = append(rr.values, value)
+}
+
+func (rr *requestRecorder) recorded() []string {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	return append([]string{}, rr.values...)
+}
+
+func (rr *requestRecorder) reset() {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	rr.values = nil
+}
+
 func TestImageWithTag(t *testing.T) {
 	assert.Equal(t, imageWithTag("selenoid/firefox", "tag"), "selenoid/firefox:tag")
 }
@@ -294,10 +392,22 @@ func TestPullImages(t *testing.T) {
 	c, err := NewDockerConfigurator(&lcConfig)
 	assert.NoError(t, err)
 	defer c.Close()
-	tags := c.pullImages("selenoid/firefox", []string{"46.0", "45.0"})
-	assert.Len(t, tags, 2)
-	assert.Equal(t, tags[0], "46.0")
-	assert.Equal(t, tags[1], "45.0")
+	failed := c.pullImages(context.Background(), []string{"selenoid/firefox:46.0", "selenoid/firefox:45.0"})
+	assert.Empty(t, failed)
+}
+
+func TestPullImagesInParallel(t *testing.T) {
+	lcConfig := LifecycleConfig{
+		RegistryUrl: mockDockerServer.URL,
+		Parallel:    2,
+	}
+	c, err := NewDockerConfigurator(&lcConfig)
+	assert.NoError(t, err)
+	defer c.Close()
+	failed := c.pullImages(context.Background(), []string{"selenoid/firefox:46.0", "selenoid/missing:1.0", "selenoid/opera:44.0", "selenoid/firefox:46.0"})
+	assert.Len(t, failed, 1)
+	assert.EqualError(t, failed["selenoid/missing:1.0"], "manifest unknown")
+	assert.EqualError(t, c.pullImage(context.Background(), "selenoid/missing:1.0"), "manifest unknown")
 }
 
 func TestConfigureDocker(t *testing.T) {
@@ -322,8 +432,8 @@ func testConfigure(t *testing.T, download bool) {
 			Browsers:     "firefox:>45.0;opera;android;MicrosoftEdge",
 			Args:         "-limit 42",
 			VNC:          true,
-			Env:          testEnv,
-			BrowserEnv:   testEnv,
+			Env:          []string{testEnv},
+			BrowserEnv:   []string{testEnv},
 		}
 		c, err := NewDockerConfigurator(&lcConfig)
 		assert.NoError(t, err)
@@ -464,7 +574,17 @@ func TestStartStopContainer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, c.IsRunning())
 	assert.NoError(t, c.Start())
-	c.Status()
+	status := c.Status()
+	assert.Equal(t, StateRunning, status.State)
+	assert.Equal(t, ModeDocker, status.Mode)
+	assert.Equal(t, "e90e34656806", status.ContainerID)
+	assert.Equal(t, DefaultPort, status.Port)
+	assert.NotNil(t, status.Image)
+	assert.NotNil(t, status.StartedAt)
+	assert.NotEmpty(t, status.Uptime)
+	var logs bytes.Buffer
+	assert.NoError(t, c.Logs(&logs, LogsOptions{Tail: 10}))
+	assert.Equal(t, "Selenoid logs...\n", logs.String())
 	assert.NoError(t, c.Stop())
 }
 
@@ -484,7 +604,9 @@ func TestStartStopUIContainer(t *testing.T) {
 	setPort(UIDefaultPort)
 	assert.True(t, c.IsUIRunning())
 	assert.NoError(t, c.StartUI())
-	c.UIStatus()
+	uiStatus := c.UIStatus()
+	assert.Equal(t, StateRunning, uiStatus.State)
+	assert.Equal(t, UIDefaultPort, uiStatus.Port)
 	assert.NoError(t, c.StopUI())
 }
 
@@ -643,3 +765,23 @@ func TestParseRequestedBrowsers(t *testing.T) {
 	assert.NotNil(t, android)
 	assert.Len(t, android, 1)
 }
+
+func TestReloadContainer(t *testing.T) {
+	var signals requestRecorder
+	srv := mockDockerServerWith(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == "/v1.29/containers/e90e34656806/kill" {
+			signals.record(r.URL.Query().Get("signal"))
+			w.WriteHeader(http.StatusNoContent)
+			return true
+		}
+		return false
+	})
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{Quiet: true})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.NoError(t, c.Reload())
+	assert.Equal(t, []string{"HUP"}, signals.recorded())
+}
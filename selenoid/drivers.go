@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -53,6 +54,13 @@ type Architectures map[string]Driver
 type Driver struct {
 	URL      string `json:"url"`
 	Filename string `json:"filename"`
+	Sha256   string `json:"sha256,omitempty"`
+}
+
+type releaseAsset struct {
+	URL     string
+	Sha256  string
+	Version string
 }
 
 type downloadedDriver struct {
@@ -72,11 +80,19 @@ type DriversConfigurator struct {
 	RequestedBrowsersAware
 	LogsAware
 	GracefulAware
+	InstanceAware
+	RetryAware
 	DriversInfoUrl string
+	CacheDir       string
 
 	GithubBaseUrl string
 	OS            string
 	Arch          string
+	Adopt         bool
+	Detach        bool
+
+	process   *startedProcess
+	uiProcess *startedProcess
 }
 
 func NewDriversConfigurator(config *LifecycleConfig) *DriversConfigurator {
@@ -92,47 +108,68 @@ func NewDriversConfigurator(config *LifecycleConfig) *DriversConfigurator {
 		RequestedBrowsersAware: RequestedBrowsersAware{Browsers: config.Browsers},
 		LogsAware:              LogsAware{DisableLogs: config.DisableLogs},
 		GracefulAware:          GracefulAware{Graceful: config.Graceful, GracefulTimeout: config.GracefulTimeout},
+		InstanceAware:          InstanceAware{Instance: config.Instance},
+		RetryAware:             RetryAware{Retry: config.Retry},
 		DriversInfoUrl:         config.DriversInfoUrl,
+		CacheDir:               config.CacheDir,
 		GithubBaseUrl:          config.GithubBaseUrl,
 		OS:                     config.OS,
 		Arch:                   config.Arch,
+		Adopt:                  config.Adopt,
+		Detach:                 config.Detach,
 	}
 }
 
-func (d *DriversConfigurator) Status() {
+func (d *DriversConfigurator) Status() *Status {
+	status := &Status{
+		Service:   "Selenoid",
+		Instance:  d.Instance,
+		Mode:      ModeDrivers,
+		ConfigDir: d.ConfigDir,
+	}
 	binaryPath := d.getSelenoidBinaryPath()
 	if fileExists(binaryPath) {
-		d.Pointf("Selenoid binary is %s", binaryPath)
-	} else {
-		d.Pointf("Selenoid binary is not downloaded")
+		status.Binary = binaryPath
 	}
 	configPath := getSelenoidConfigPath(d.ConfigDir)
-	d.Pointf("Selenoid configuration directory is %s", d.ConfigDir)
 	if fileExists(configPath) {
-		d.Pointf("Selenoid configuration file is %s", configPath)
-	} else {
-		d.Pointf("Selenoid is not configured")
+		status.ConfigPath = configPath
+		status.Browsers = readBrowsersStatus(configPath)
 	}
-	selenoidProcesses := findSelenoidProcesses()
+	selenoidProcesses := d.findSelenoidProcesses()
 	if len(selenoidProcesses) > 0 {
-		d.Pointf("Selenoid is running as process %d", selenoidProcesses[0].Pid)
-	} else {
-		d.Pointf("Selenoid is not running")
+		status.Pid = selenoidProcesses[0].Pid
+		status.Port = d.Port
+		d.fillStartedAt(status, selenoidStateFileName)
 	}
+	status.setState(status.Pid != 0, status.Binary != "" && status.ConfigPath != "")
+	return status
 }
 
-func (d *DriversConfigurator) UIStatus() {
+func (d *DriversConfigurator) UIStatus() *Status {
+	status := &Status{
+		Service:  "Selenoid UI",
+		Instance: d.Instance,
+		Mode:     ModeDrivers,
+	}
 	binaryPath := d.getSelenoidUIBinaryPath()
 	if fileExists(binaryPath) {
-		d.Pointf("Selenoid UI binary is %s", binaryPath)
-	} else {
-		d.Pointf("Selenoid UI binary is not downloaded")
+		status.Binary = binaryPath
 	}
-	selenoidUIProcesses := findSelenoidUIProcesses()
+	selenoidUIProcesses := d.findSelenoidUIProcesses()
 	if len(selenoidUIProcesses) > 0 {
-		d.Pointf("Selenoid UI is running as process %d", selenoidUIProcesses[0].Pid)
-	} else {
-		d.Pointf("Selenoid UI is not running")
+		status.Pid = selenoidUIProcesses[0].Pid
+		status.Port = d.Port
+		d.fillStartedAt(status, selenoidUIStateFileName)
+	}
+	status.setState(status.Pid != 0, status.Binary != "")
+	return status
+}
+
+func (d *DriversConfigurator) fillStartedAt(status *Status, stateFileName string) {
+	state, err := readProcessState(d.getStateFilePath(stateFileName))
+	if err == nil && state.Pid == status.Pid {
+		status.setStartedAt(state.StartedAt)
 	}
 }
 
@@ -161,7 +198,7 @@ func getSelenoidConfigPath(outputDir string) string {
 }
 
 func (d *DriversConfigurator) Download() (string, error) {
-	u, err := d.getSelenoidUrl()
+	asset, err := d.getSelenoidUrl()
 	if err != nil {
 		return "", fmt.Errorf("failed to get Selenoid download URL for arch = %s and version = %s: %v", d.Arch, d.Version, err)
 	}
@@ -176,21 +213,21 @@ func (d *DriversConfigurator) Download() (string, error) {
 			return "", fmt.Errorf("failed to stop Selenoid: %v", err)
 		}
 	}
-	d.Titlef("Downloading Selenoid release from %s", color.BlueString(u))
-	outputFile, err := d.downloadFile(u, d.getSelenoidBinaryPath())
+	d.Titlef("Downloading Selenoid release from %s", color.BlueString(asset.URL))
+	outputFile, err := d.downloadFile(asset, d.getSelenoidBinaryPath())
 	if err != nil {
 		return "", fmt.Errorf("failed to download Selenoid for arch = %s and version = %s: %v", d.Arch, d.Version, err)
 	}
 	d.Titlef("Successfully downloaded Selenoid to %s", color.GreenString(outputFile))
 	return outputFile, nil
 }
-func (d *DriversConfigurator) getSelenoidUrl() (string, error) {
+func (d *DriversConfigurator) getSelenoidUrl() (*releaseAsset, error) {
 	d.Titlef("Getting Selenoid release information for version: %s", d.Version)
 	return d.getUrl(selenoidRepo, fmt.Errorf("Selenoid binary for %s %s is not available for specified release: %s", strings.Title(d.OS), d.Arch, d.Version))
 }
 
 func (d *DriversConfigurator) DownloadUI() (string, error) {
-	u, err := d.getSelenoidUIUrl()
+	asset, err := d.getSelenoidUIUrl()
 	if err != nil {
 		return "", fmt.Errorf("failed to get download URL for arch = %s and version = %s: %v", d.Arch, d.Version, err)
 	}
@@ -205,8 +242,8 @@ func (d *DriversConfigurator) DownloadUI() (string, error) {
 			return "", fmt.Errorf("failed to stop Selenoid UI: %v", err)
 		}
 	}
-	d.Titlef("Downloading Selenoid UI release from %s", color.BlueString(u))
-	outputFile, err := d.downloadFile(u, d.getSelenoidUIBinaryPath())
+	d.Titlef("Downloading Selenoid UI release from %s", color.BlueString(asset.URL))
+	outputFile, err := d.downloadFile(asset, d.getSelenoidUIBinaryPath())
 	if err != nil {
 		return "", fmt.Errorf("failed to download Selenoid UI for arch = %s and version = %s: %v", d.Arch, d.Version, err)
 	}
@@ -218,60 +255,122 @@ var (
 	title = cases.Title(language.AmericanEnglish)
 )
 
-func (d *DriversConfigurator) getSelenoidUIUrl() (string, error) {
+func (d *DriversConfigurator) getSelenoidUIUrl() (*releaseAsset, error) {
 	d.Titlef("Getting Selenoid UI release information for version: %s", color.BlueString(d.Version))
 	return d.getUrl(selenoidUIRepo, fmt.Errorf("selenoid ui binary for %s %s is not available for specified release: %s", title.String(d.OS), d.Arch, d.Version))
 }
 
-func (d *DriversConfigurator) getUrl(repo string, missingBinaryError error) (string, error) {
+// getUrl falls back to release asset resolved earlier when GitHub is not available, so that cached files work offline
+func (d *DriversConfigurator) getUrl(repo string, missingBinaryError error) (*releaseAsset, error) {
+	cache := d.cache()
+	key := strings.Join([]string{repo, d.Version, d.OS, d.Arch}, "/")
+	asset, err := d.fetchReleaseAsset(repo, missingBinaryError)
+	if err == nil {
+		cache.saveRelease(key, asset)
+		return asset, nil
+	}
+	if cached := cache.release(key); cached != nil && err != missingBinaryError {
+		d.Errorf("Failed to get release information, using cached one: %v", err)
+		return cached, nil
+	}
+	return nil, err
+}
+
+func (d *DriversConfigurator) fetchReleaseAsset(repo string, missingBinaryError error) (*releaseAsset, error) {
 	ctx := context.Background()
 	client := github.NewClient(nil)
 	if d.GithubBaseUrl != "" {
 		u, err := url.Parse(d.GithubBaseUrl)
 		if err != nil {
-			return "", fmt.Errorf("invalid Github base url [%s]: %v", d.GithubBaseUrl, err)
+			return nil, fmt.Errorf("invalid Github base url [%s]: %v", d.GithubBaseUrl, err)
 		}
 		client.BaseURL = u
 	}
 	var release *github.RepositoryRelease
-	var err error
-	if d.Version != Latest {
-		release, _, err = client.Repositories.GetReleaseByTag(ctx, owner, repo, d.Version)
-	} else {
-		release, _, err = client.Repositories.GetLatestRelease(ctx, owner, repo)
-	}
+	err := d.Retry.do(ctx, &d.Logger, func() error {
+		var err error
+		if d.Version != Latest {
+			release, _, err = client.Repositories.GetReleaseByTag(ctx, owner, repo, d.Version)
+		} else {
+			release, _, err = client.Repositories.GetLatestRelease(ctx, owner, repo)
+		}
+		return err
+	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if release == nil {
-		return "", fmt.Errorf("unknown release: %s", d.Version)
+		return nil, fmt.Errorf("unknown release: %s", d.Version)
 	}
 
 	for _, asset := range release.Assets {
-		assetName := *(asset.Name)
-		if strings.Contains(assetName, d.OS) && strings.Contains(assetName, d.Arch) {
-			return *(asset.BrowserDownloadURL), nil
+		assetName := asset.GetName()
+		if !isChecksumAsset(assetName) && strings.Contains(assetName, d.OS) && strings.Contains(assetName, d.Arch) {
+			checksum, err := d.getReleaseChecksum(release.Assets, assetName)
+			if err != nil {
+				return nil, err
+			}
+			return &releaseAsset{URL: asset.GetBrowserDownloadURL(), Sha256: checksum, Version: release.GetTagName()}, nil
 		}
 	}
-	return "", missingBinaryError
+	return nil, missingBinaryError
 }
 
-func (d *DriversConfigurator) downloadFile(url string, outputPath string) (string, error) {
-	f, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+// getReleaseChecksum looks for asset checksum in <asset>.sha256 or checksums.txt files attached to release
+func (d *DriversConfigurator) getReleaseChecksum(assets []github.ReleaseAsset, assetName string) (string, error) {
+	for _, asset := range assets {
+		name := asset.GetName()
+		if !isChecksumAsset(name) {
+			continue
+		}
+		perAsset := strings.HasSuffix(strings.ToLower(name), ".sha256")
+		if perAsset && name[:len(name)-len(".sha256")] != assetName {
+			continue
+		}
+		data, err := downloadFile(asset.GetBrowserDownloadURL(), d.Retry, &d.Logger)
+		if err != nil {
+			return "", fmt.Errorf("failed to download checksums from %s: %v", name, err)
+		}
+		checksums := parseChecksums(data)
+		if checksum, ok := checksums[assetName]; ok {
+			return checksum, nil
+		}
+		if checksum, ok := checksums[""]; ok && perAsset {
+			return checksum, nil
+		}
+	}
+	return "", nil
+}
+
+func (d *DriversConfigurator) downloadFile(asset *releaseAsset, outputPath string) (string, error) {
+	cachedPath, err := d.cache().fetch(asset.URL, asset.Sha256)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(cachedPath)
 	if err != nil {
 		return "", err
 	}
 	defer f.Close()
-
-	err = downloadFileWithProgressBar(url, f)
+	err = outputFile(outputPath, os.ModePerm, f)
 	if err != nil {
 		return "", err
 	}
+	if asset.Version != "" {
+		err = os.WriteFile(outputPath+versionFileSuffix, []byte(asset.Version), 0644)
+		if err != nil {
+			return "", fmt.Errorf("failed to save version: %v", err)
+		}
+	}
 	return outputPath, nil
 }
 
+func (d *DriversConfigurator) cache() *downloadCache {
+	return &downloadCache{dir: cacheDirOrDefault(d.CacheDir, d.ConfigDir), retry: d.Retry, logger: &d.Logger}
+}
+
 func (d *DriversConfigurator) IsConfigured() bool {
 	return fileExists(getSelenoidConfigPath(d.ConfigDir))
 }
@@ -301,9 +400,8 @@ func (d *DriversConfigurator) generateConfig(downloadedDrivers []downloadedDrive
 			Image: dd.Command,
 			Path:  "/",
 		}
-		browserEnv := strings.Fields(d.BrowserEnv)
-		if len(browserEnv) > 0 {
-			browser.Env = browserEnv
+		if len(d.BrowserEnv) > 0 {
+			browser.Env = append([]string{}, d.BrowserEnv...)
 		}
 		versions := config.Versions{
 			Default: Latest,
@@ -319,11 +417,16 @@ func (d *DriversConfigurator) generateConfig(downloadedDrivers []downloadedDrive
 func (d *DriversConfigurator) loadAvailableBrowsers() (*Browsers, error) {
 	jsonUrl := d.DriversInfoUrl
 	d.Titlef("Downloading browser data from: %s", color.BlueString(jsonUrl))
-	data, err := downloadFile(jsonUrl)
+	cachedPath, err := d.cache().refresh(jsonUrl)
 	if err != nil {
 		d.Errorf("Browsers data download error: %v", err)
 		return nil, err
 	}
+	data, err := os.ReadFile(cachedPath)
+	if err != nil {
+		d.Errorf("Browsers data read error: %v", err)
+		return nil, err
+	}
 	var browsers Browsers
 	err = json.Unmarshal(data, &browsers)
 	if err != nil {
@@ -333,14 +436,20 @@ func (d *DriversConfigurator) loadAvailableBrowsers() (*Browsers, error) {
 	return &browsers, nil
 }
 
-func downloadFile(url string) ([]byte, error) {
+func downloadFile(url string, retry RetryPolicy, logger *Logger) ([]byte, error) {
 	var b bytes.Buffer
-	w := bufio.NewWriter(&b)
-	err := downloadFileWithProgressBar(url, w)
+	err := retry.do(context.Background(), logger, func() error {
+		b.Reset()
+		w := bufio.NewWriter(&b)
+		err := downloadFileWithProgressBar(url, w)
+		if err != nil {
+			return err
+		}
+		return w.Flush()
+	})
 	if err != nil {
 		return nil, err
 	}
-	_ = w.Flush()
 	return b.Bytes(), nil
 }
 
@@ -351,8 +460,8 @@ func downloadFileWithProgressBar(url string, w io.Writer) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return newHttpStatusError(resp)
 	}
 
 	contentLength := int(resp.ContentLength)
@@ -380,12 +489,12 @@ func (d *DriversConfigurator) downloadDriver(driver *Driver, dir string) (string
 	}
 	if d.DownloadNeeded {
 		d.Pointf("Downloading driver from %s...", color.BlueString(driver.URL))
-		data, err := downloadFile(driver.URL)
+		cachedPath, err := d.cache().fetch(driver.URL, driver.Sha256)
 		if err != nil {
 			return "", fmt.Errorf("failed to download driver archive: %v", err)
 		}
 		d.Pointf("Unpacking archive to %s...", color.BlueString(dir))
-		return extractFile(data, driver.Filename, dir)
+		return extractFile(cachedPath, driver.Filename, dir)
 	}
 	return filepath.Join(dir, driver.Filename), nil
 }
@@ -405,14 +514,29 @@ func isTarGzFile(data []byte) bool {
 	return getMagicHeader(data) == gzipMagicHeader
 }
 
-func extractFile(data []byte, filename string, outputDir string) (string, error) {
-	if isZipFile(data) {
-		return unzip(data, filename, outputDir)
-	} else if isTarGzFile(data) {
-		return untar(data, filename, outputDir)
+func extractFile(archivePath string, filename string, outputDir string) (string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	header := make([]byte, 2)
+	n, _ := io.ReadFull(f, header)
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+	if isZipFile(header[:n]) {
+		return unzip(f, fi.Size(), filename, outputDir)
+	} else if isTarGzFile(header[:n]) {
+		return untar(f, filename, outputDir)
 	} else {
 		outputPath := filepath.Join(outputDir, filename)
-		err := os.WriteFile(outputPath, data, os.ModePerm)
+		err := outputFile(outputPath, os.ModePerm, f)
 		if err != nil {
 			return "", fmt.Errorf("failed to save file %s: %v", outputPath, err)
 		}
@@ -421,8 +545,8 @@ func extractFile(data []byte, filename string, outputDir string) (string, error)
 }
 
 // Based on http://stackoverflow.com/questions/20357223/easy-way-to-unzip-file-with-golang
-func unzip(data []byte, fileName string, outputDir string) (string, error) {
-	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+func unzip(r io.ReaderAt, size int64, fileName string, outputDir string) (string, error) {
+	zr, err := zip.NewReader(r, size)
 
 	// Closure to address file descriptors issue with all the deferred .Close() methods
 	extractAndWriteFile := func(f *zip.File) (string, error) {
@@ -458,9 +582,9 @@ func unzip(data []byte, fileName string, outputDir string) (string, error) {
 }
 
 // Based on https://medium.com/@skdomino/taring-untaring-files-in-go-6b07cf56bc07
-func untar(data []byte, fileName string, outputDir string) (string, error) {
+func untar(r io.Reader, fileName string, outputDir string) (string, error) {
 
-	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	gzr, err := gzip.NewReader(r)
 	defer gzr.Close()
 
 	extractAndWriteFile := func(tr *tar.Reader, header *tar.Header) (string, error) {
@@ -516,8 +640,7 @@ func outputFile(outputPath string, mode os.FileMode, r io.Reader) error {
 	return nil
 }
 
-func (d *DriversConfigurator) downloadDrivers(browsers *Browsers, configDir string) []downloadedDriver {
-	var ret []downloadedDriver
+func (d *DriversConfigurator) getBrowsersToIterate(browsers *Browsers) Browsers {
 	browsersToIterate := *browsers
 	if d.Browsers != "" {
 		requestedBrowsers := parseRequestedBrowsers(&d.Logger, d.Browsers)
@@ -532,9 +655,13 @@ func (d *DriversConfigurator) downloadDrivers(browsers *Browsers, configDir stri
 			}
 		}
 	}
+	return browsersToIterate
+}
 
+func (d *DriversConfigurator) downloadDrivers(browsers *Browsers, configDir string) []downloadedDriver {
+	var ret []downloadedDriver
 loop:
-	for browserName, browser := range browsersToIterate {
+	for browserName, browser := range d.getBrowsersToIterate(browsers) {
 		goos := runtime.GOOS
 		goarch := runtime.GOARCH
 		if architectures, ok := browser.Files[goos]; ok {
@@ -555,6 +682,57 @@ loop:
 	return ret
 }
 
+func (d *DriversConfigurator) InstalledVersion() string {
+	return installedBinaryVersion(d.getSelenoidBinaryPath())
+}
+
+func (d *DriversConfigurator) InstalledUIVersion() string {
+	return installedBinaryVersion(d.getSelenoidUIBinaryPath())
+}
+
+// installedBinaryVersion returns release version saved on download
+func installedBinaryVersion(binaryPath string) string {
+	if !fileExists(binaryPath) {
+		return ""
+	}
+	data, err := os.ReadFile(binaryPath + versionFileSuffix)
+	if err != nil {
+		return unknownVersion
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func (d *DriversConfigurator) LatestVersion() (string, error) {
+	asset, err := d.getSelenoidUrl()
+	if err != nil {
+		return "", err
+	}
+	return asset.Version, nil
+}
+
+func (d *DriversConfigurator) LatestUIVersion() (string, error) {
+	asset, err := d.getSelenoidUIUrl()
+	if err != nil {
+		return "", err
+	}
+	return asset.Version, nil
+}
+
+// AvailableBrowsers returns browsers that Configure would add without downloading drivers
+func (d *DriversConfigurator) AvailableBrowsers() (map[string][]string, error) {
+	browsers, err := d.loadAvailableBrowsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load available browsers: %v", err)
+	}
+	ret := make(map[string][]string)
+	for browserName, browser := range d.getBrowsersToIterate(browsers) {
+		if _, ok := browser.Files[runtime.GOOS][runtime.GOARCH]; ok {
+			ret[browserName] = []string{Latest}
+		}
+	}
+	return ret, nil
+}
+
 func prepareCommand(cmd string, driverPath string) []string {
 	var ret []string
 	for _, p := range strings.Fields(cmd) {
@@ -568,12 +746,12 @@ func prepareCommand(cmd string, driverPath string) []string {
 }
 
 func (d *DriversConfigurator) IsRunning() bool {
-	selenoidProcesses := findSelenoidProcesses()
+	selenoidProcesses := d.findSelenoidProcesses()
 	return len(selenoidProcesses) > 0
 }
 
 func (d *DriversConfigurator) IsUIRunning() bool {
-	selenoidUIProcesses := findSelenoidUIProcesses()
+	selenoidUIProcesses := d.findSelenoidUIProcesses()
 	return len(selenoidUIProcesses) > 0
 }
 
@@ -582,6 +760,19 @@ func (d *DriversConfigurator) PrintArgs() error {
 }
 
 func (d *DriversConfigurator) Start() error {
+	output, err := d.serviceOutput(selenoidLogFileName)
+	if err != nil {
+		return err
+	}
+	p, err := startProcess(d.getSelenoidBinaryPath(), d.selenoidArgs(), d.Env, output)
+	if err != nil {
+		return err
+	}
+	d.process = p
+	return writeProcessState(d.getStateFilePath(selenoidStateFileName), p, false)
+}
+
+func (d *DriversConfigurator) selenoidArgs() []string {
 	args := []string{}
 	overrideArgs := strings.Fields(d.Args)
 	if len(overrideArgs) > 0 {
@@ -597,12 +788,22 @@ func (d *DriversConfigurator) Start() error {
 		args = append(args, "-disable-docker")
 	}
 	if !d.DisableLogs && !contains(args, "-log-output-dir") && isLogSavingSupported(d.Logger, d.Version) {
-		logsConfigDir := getVolumeConfigDir(filepath.Join(d.ConfigDir, logsDirName), append(selenoidConfigDirElem, logsDirName))
+		logsConfigDir := getVolumeConfigDir(filepath.Join(d.ConfigDir, logsDirName), withElem(getInstanceConfigDirElem(d.Instance, selenoidConfigDirElem), logsDirName))
 		args = append(args, "-log-output-dir", logsConfigDir)
 	}
+	return args
+}
 
-	env := strings.Fields(d.Env)
-	return runCommand(d.getSelenoidBinaryPath(), args, env)
+// serviceOutput returns pipe to log writer for detached process and nil when output should go to the terminal
+func (d *DriversConfigurator) serviceOutput(logFileName string) (*os.File, error) {
+	if !d.Detach {
+		return nil, nil
+	}
+	return startLogWriter(d.getServiceLogPath(logFileName))
+}
+
+func (d *DriversConfigurator) getServiceLogPath(logFileName string) string {
+	return filepath.Join(d.ConfigDir, logsDirName, logFileName)
 }
 
 func contains(haystack []string, needle string) bool {
@@ -619,12 +820,24 @@ func (d *DriversConfigurator) PrintUIArgs() error {
 }
 
 func (d *DriversConfigurator) StartUI() error {
+	output, err := d.serviceOutput(selenoidUILogFileName)
+	if err != nil {
+		return err
+	}
+	p, err := startProcess(d.getSelenoidUIBinaryPath(), d.selenoidUIArgs(), d.Env, output)
+	if err != nil {
+		return err
+	}
+	d.uiProcess = p
+	return writeProcessState(d.getStateFilePath(selenoidUIStateFileName), p, false)
+}
+
+func (d *DriversConfigurator) selenoidUIArgs() []string {
 	args := strings.Fields(d.Args)
 	if !contains(args, "-listen") {
 		args = append(args, "-listen", fmt.Sprintf(":%d", d.Port))
 	}
-	env := strings.Fields(d.Env)
-	return runCommand(d.getSelenoidUIBinaryPath(), args, env)
+	return args
 }
 
 var killFunc = func(p *os.Process, graceful bool, gracefulTimeout time.Duration) error {
@@ -652,11 +865,41 @@ var killFunc = func(p *os.Process, graceful bool, gracefulTimeout time.Duration)
 }
 
 func (d *DriversConfigurator) Stop() error {
-	return d.killAllProcesses(findSelenoidProcesses())
+	err := d.killSupervisor(selenoidStateFileName)
+	if err != nil {
+		return err
+	}
+	err = d.killAllProcesses(d.findSelenoidProcesses())
+	if err != nil {
+		return err
+	}
+	return removeProcessState(d.getStateFilePath(selenoidStateFileName))
+}
+
+// Reload sends SIGHUP to Selenoid process, supervisor is not signaled as it only restarts exited process
+func (d *DriversConfigurator) Reload() error {
+	if isWindows() {
+		return errors.New("reloading configuration is not supported on Windows")
+	}
+	processes := d.findSelenoidProcesses()
+	if len(processes) == 0 {
+		return errors.New("selenoid process is not running")
+	}
+	for _, p := range processes {
+		err := p.Signal(syscall.SIGHUP)
+		if err != nil {
+			return fmt.Errorf("failed to send signal: %v", err)
+		}
+	}
+	return nil
 }
 
 func (d *DriversConfigurator) StopUI() error {
-	return d.killAllProcesses(findSelenoidUIProcesses())
+	err := d.killAllProcesses(d.findSelenoidUIProcesses())
+	if err != nil {
+		return err
+	}
+	return removeProcessState(d.getStateFilePath(selenoidUIStateFileName))
 }
 
 func (d *DriversConfigurator) killAllProcesses(processes []*os.Process) error {
@@ -669,11 +912,140 @@ func (d *DriversConfigurator) killAllProcesses(processes []*os.Process) error {
 	return nil
 }
 
+// killSupervisor kills supervisor immediately so that it does not restart stopped process
+func (d *DriversConfigurator) killSupervisor(stateFileName string) error {
+	state, err := readProcessState(d.getStateFilePath(stateFileName))
+	if err != nil || state.SupervisorPid == 0 {
+		return nil
+	}
+	p := findVerifiedProcess(state.SupervisorPid, state.SupervisorBinary)
+	if p == nil {
+		return nil
+	}
+	err = killFunc(p, false, 0)
+	if err != nil {
+		return fmt.Errorf("failed to stop supervisor: %v", err)
+	}
+	return nil
+}
+
 func (d *DriversConfigurator) Close() error {
 	//Does nothing
 	return nil
 }
 
+const (
+	selenoidStateFileName   = "selenoid.state.json"
+	selenoidUIStateFileName = "selenoid-ui.state.json"
+
+	// Linux truncates process names to this length
+	maxProcessNameLength = 15
+)
+
+// processState describes process started by drivers configurator
+type processState struct {
+	Pid              int       `json:"pid"`
+	Binary           string    `json:"binary"`
+	StartedAt        time.Time `json:"startedAt"`
+	Args             []string  `json:"args"`
+	SupervisorPid    int       `json:"supervisorPid,omitempty"`
+	SupervisorBinary string    `json:"supervisorBinary,omitempty"`
+}
+
+func (d *DriversConfigurator) getStateFilePath(name string) string {
+	return filepath.Join(d.ConfigDir, name)
+}
+
+func (d *DriversConfigurator) findSelenoidProcesses() []*os.Process {
+	return d.findInstanceProcesses(selenoidStateFileName, findSelenoidProcesses)
+}
+
+func (d *DriversConfigurator) findSelenoidUIProcesses() []*os.Process {
+	return d.findInstanceProcesses(selenoidUIStateFileName, findSelenoidUIProcesses)
+}
+
+// findInstanceProcesses uses state file and falls back to process name matching only when adopting processes
+func (d *DriversConfigurator) findInstanceProcesses(stateFileName string, fallback func() []*os.Process) []*os.Process {
+	state, err := readProcessState(d.getStateFilePath(stateFileName))
+	if err == nil {
+		p := findStateProcess(state)
+		if p != nil {
+			return []*os.Process{p}
+		}
+	}
+	if d.Adopt {
+		return fallback()
+	}
+	return nil
+}
+
+// findStateProcess returns process only if its PID still belongs to recorded executable
+func findStateProcess(state *processState) *os.Process {
+	return findVerifiedProcess(state.Pid, state.Binary)
+}
+
+func findVerifiedProcess(pid int, binary string) *os.Process {
+	process, err := ps.FindProcess(pid)
+	if err != nil || process == nil {
+		return nil
+	}
+	if !isSameExecutable(process.Executable(), binary) {
+		return nil
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+func isSameExecutable(executable string, binary string) bool {
+	name := filepath.Base(binary)
+	if executable == name {
+		return true
+	}
+	return len(executable) == maxProcessNameLength && strings.HasPrefix(name, executable)
+}
+
+func readProcessState(stateFile string) (*processState, error) {
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	var state processState
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse process state: %v", err)
+	}
+	return &state, nil
+}
+
+func writeProcessState(stateFile string, p *startedProcess, supervised bool) error {
+	state := processState{
+		Pid:       p.cmd.Process.Pid,
+		Binary:    p.cmd.Path,
+		StartedAt: time.Now(),
+		Args:      p.cmd.Args[1:],
+	}
+	if supervised {
+		state.SupervisorPid = os.Getpid()
+		state.SupervisorBinary, _ = os.Executable()
+	}
+	data, err := json.MarshalIndent(state, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %v", err)
+	}
+	return os.WriteFile(stateFile, data, 0644)
+}
+
+func removeProcessState(stateFile string) error {
+	err := os.Remove(stateFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 func findSelenoidProcesses() []*os.Process {
 	return findProcesses("selenoid")
 }
@@ -700,12 +1072,75 @@ func findProcesses(regex string) []*os.Process {
 var execCommand = exec.Command
 
 func runCommand(command string, args []string, env []string) error {
+	_, err := startProcess(command, args, env, nil)
+	return err
+}
+
+type startedProcess struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// startProcess attaches process to the terminal when output is nil and detaches it otherwise
+func startProcess(command string, args []string, env []string, output *os.File) (*startedProcess, error) {
 	cmd := execCommand(command, args...)
+	cmd.Env = env
+	if output != nil {
+		defer output.Close()
+		cmd.Stdout = output
+		cmd.Stderr = output
+		cmd.SysProcAttr = detachedProcAttr()
+		return startCommand(cmd)
+	}
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	cmd.Env = env
-	return cmd.Start()
+	return startCommand(cmd)
+}
+
+func startCommand(cmd *exec.Cmd) (*startedProcess, error) {
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	p := &startedProcess{cmd: cmd, done: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+func (p *startedProcess) describe(w io.Writer) error {
+	if p == nil {
+		return errors.New("process was not started by this command")
+	}
+	select {
+	case <-p.done:
+		_, err := fmt.Fprintf(w, "Process %d exited: %v\n", p.cmd.Process.Pid, p.err)
+		return err
+	default:
+		_, err := fmt.Fprintf(w, "Process %d is running, its output is printed to the terminal\n", p.cmd.Process.Pid)
+		return err
+	}
+}
+
+func (d *DriversConfigurator) Logs(w io.Writer, opts LogsOptions) error {
+	return d.serviceLogs(selenoidLogFileName, d.process, w, opts)
+}
+
+func (d *DriversConfigurator) UILogs(w io.Writer, opts LogsOptions) error {
+	return d.serviceLogs(selenoidUILogFileName, d.uiProcess, w, opts)
+}
+
+// serviceLogs describes process attached to the terminal and reads log file of detached or supervised process
+func (d *DriversConfigurator) serviceLogs(logFileName string, p *startedProcess, w io.Writer, opts LogsOptions) error {
+	logPath := d.getServiceLogPath(logFileName)
+	if (p == nil || d.Detach) && fileExists(logPath) {
+		return tailLogFile(logPath, w, opts)
+	}
+	return p.describe(w)
 }
 
 func getSelenoidReleaseFileName() string {
@@ -1,6 +1,8 @@
 package selenoid
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,8 +11,10 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -118,6 +122,14 @@ func driversMux() http.Handler {
 		},
 	))
 
+	mux.HandleFunc("/checksums.txt", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			checksum := sha256.Sum256([]byte(r.URL.Query().Get(version)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprintf(w, "%x  %s\n", checksum, releaseFileName)
+		},
+	))
+
 	//Serving static files from current directory
 	mux.Handle("/", http.FileServer(http.Dir("")))
 
@@ -175,8 +187,8 @@ func TestConfigureDrivers(t *testing.T) {
 			Download:       true,
 			Quiet:          false,
 			Args:           "-limit 42",
-			Env:            testEnv,
-			BrowserEnv:     testEnv,
+			Env:            []string{testEnv},
+			BrowserEnv:     []string{testEnv},
 		}
 		configurator := NewDriversConfigurator(&lcConfig)
 		assert.False(t, configurator.IsConfigured())
@@ -242,7 +254,7 @@ func TestUnzip(t *testing.T) {
 	assert.True(t, isZipFile(data))
 	assert.False(t, isTarGzFile(data))
 	testUnpack(t, data, "zip-testfile", func(data []byte, filePath string, outputDir string) (string, error) {
-		return unzip(data, filePath, outputDir)
+		return unzip(bytes.NewReader(data), int64(len(data)), filePath, outputDir)
 	}, "zip\n")
 }
 
@@ -251,7 +263,7 @@ func TestUntar(t *testing.T) {
 	assert.True(t, isTarGzFile(data))
 	assert.False(t, isZipFile(data))
 	testUnpack(t, data, "gzip-testfile", func(data []byte, filePath string, outputDir string) (string, error) {
-		return untar(data, filePath, outputDir)
+		return untar(bytes.NewReader(data), filePath, outputDir)
 	}, "gzip\n")
 }
 
@@ -285,7 +297,7 @@ func readFile(t *testing.T, fileName string) []byte {
 
 func TestDownloadFile(t *testing.T) {
 	fileUrl := mockServerUrl(mockDriverServer, "/testfile")
-	data, err := downloadFile(fileUrl)
+	data, err := downloadFile(fileUrl, RetryPolicy{}, nil)
 	if err != nil {
 		t.Fatalf("failed to download file: %v\n", err)
 	}
@@ -314,12 +326,22 @@ func getReleaseHandler(v string) func(http.ResponseWriter, *http.Request) {
 			mockDriverServer,
 			fmt.Sprintf("/%s?%s=%s", releaseFileName, version, v),
 		)
+		checksumsName := "checksums.txt"
+		checksumsUrl := mockServerUrl(
+			mockDriverServer,
+			fmt.Sprintf("/%s?%s=%s", checksumsName, version, v),
+		)
 		release := github.RepositoryRelease{
+			TagName: &v,
 			Assets: []github.ReleaseAsset{
 				{
 					Name:               &releaseFileName,
 					BrowserDownloadURL: &releaseUrl,
 				},
+				{
+					Name:               &checksumsName,
+					BrowserDownloadURL: &checksumsUrl,
+				},
 			},
 		}
 		data, _ := json.Marshal(&release)
@@ -361,6 +383,35 @@ func testDownloadRelease(t *testing.T, desiredVersion string, expectedFileConten
 
 }
 
+func TestDownloadCachedReleaseOffline(t *testing.T) {
+	withTmpDir(t, "downloader", func(t *testing.T, dir string) {
+		lcConfig := LifecycleConfig{
+			GithubBaseUrl: mockDriverServer.URL + "/",
+			ConfigDir:     dir,
+			OS:            runtime.GOOS,
+			Arch:          runtime.GOARCH,
+			Version:       previousReleaseTag,
+			Quiet:         true,
+		}
+		_, err := NewDriversConfigurator(&lcConfig).Download()
+		assert.NoError(t, err)
+
+		offline := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		offline.Close()
+		lcConfig.GithubBaseUrl = offline.URL + "/"
+		lcConfig.Force = true
+		outputPath, err := NewDriversConfigurator(&lcConfig).Download()
+		assert.NoError(t, err)
+		checkContentsEqual(t, outputPath, previousReleaseTag)
+
+		lcConfig.Version = Latest
+		_, err = NewDriversConfigurator(&lcConfig).Download()
+		assert.Error(t, err)
+	})
+}
+
 func checkContentsEqual(t *testing.T, outputPath string, expectedFileContents string) {
 	if !fileExists(outputPath) {
 		t.Fatalf("release was not downloaded to %s: file does not exist\n", outputPath)
@@ -434,9 +485,16 @@ func TestStartStopProcess(t *testing.T) {
 			Port:          DefaultPort,
 		}
 		configurator := NewDriversConfigurator(&lcConfig)
+		assert.False(t, configurator.IsRunning()) // No state file yet
+		configurator.Adopt = true
 		assert.True(t, configurator.IsRunning()) //This is probably true because test binary has name selenoid.test; no fake process is launched
+		configurator.Adopt = false
 		assert.NoError(t, configurator.Start())
 		configurator.Status()
+		var logs bytes.Buffer
+		assert.NoError(t, configurator.Logs(&logs, LogsOptions{}))
+		assert.Contains(t, logs.String(), "Process")
+		assert.Error(t, configurator.UILogs(&logs, LogsOptions{}))
 		assert.NoError(t, configurator.Stop())
 		assert.NoError(t, configurator.PrintArgs())
 
@@ -458,6 +516,20 @@ func fakeExecCommand(command string, args ...string) *exec.Cmd {
 	return cmd
 }
 
+// TestHelperProcess plays log writer started by detached process, other commands only print test result
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) == 5 && args[2] == "selenoid" && args[3] == LogWriterCommand {
+		assert.NoError(t, WriteLog(args[4], os.Stdin))
+	}
+}
+
 func TestPrepareCommand(t *testing.T) {
 	assert.Equal(
 		t,
@@ -467,3 +539,115 @@ func TestPrepareCommand(t *testing.T) {
 		},
 	)
 }
+
+func TestProcessState(t *testing.T) {
+	withTmpDir(t, "state", func(t *testing.T, dir string) {
+		stateFile := filepath.Join(dir, selenoidStateFileName)
+		_, err := readProcessState(stateFile)
+		assert.Error(t, err)
+		assert.NoError(t, writeCurrentProcessState(stateFile))
+		state, err := readProcessState(stateFile)
+		assert.NoError(t, err)
+		assert.Equal(t, os.Getpid(), state.Pid)
+		p := findStateProcess(state)
+		assert.NotNil(t, p)
+		assert.Equal(t, os.Getpid(), p.Pid)
+
+		state.Binary = "/path/to/selenoid-ui"
+		assert.Nil(t, findStateProcess(state)) // PID belongs to another executable
+
+		assert.NoError(t, removeProcessState(stateFile))
+		assert.NoError(t, removeProcessState(stateFile))
+		assert.NoError(t, os.WriteFile(stateFile, []byte("not-a-json"), 0644))
+		_, err = readProcessState(stateFile)
+		assert.Error(t, err)
+	})
+}
+
+func TestIsSameExecutable(t *testing.T) {
+	assert.True(t, isSameExecutable("selenoid_linux_amd64", "/home/user/.aerokube/selenoid/selenoid_linux_amd64"))
+	assert.True(t, isSameExecutable("selenoid-ui_lin", "/home/user/.aerokube/selenoid/selenoid-ui_linux_amd64"))
+	assert.False(t, isSameExecutable("selenoid-ui_linux_amd64", "/home/user/.aerokube/selenoid/selenoid_linux_amd64"))
+	assert.False(t, isSameExecutable("selenoid", "/home/user/.aerokube/selenoid/selenoid_linux_amd64"))
+}
+
+func TestNamedInstanceProcesses(t *testing.T) {
+	withTmpDir(t, "instance", func(t *testing.T, dir string) {
+		configurator := NewDriversConfigurator(&LifecycleConfig{
+			ConfigDir: dir,
+			Instance:  "team",
+			Port:      DefaultPort,
+		})
+		assert.False(t, configurator.IsRunning())
+		assert.NoError(t, writeCurrentProcessState(configurator.getStateFilePath(selenoidStateFileName)))
+		assert.True(t, configurator.IsRunning())
+		assert.False(t, configurator.IsUIRunning())
+		status := configurator.Status()
+		assert.Equal(t, "team", status.Instance)
+		assert.Equal(t, os.Getpid(), status.Pid)
+		assert.NotNil(t, status.StartedAt)
+	})
+}
+
+func writeCurrentProcessState(stateFile string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(executable)
+	cmd.Process = &os.Process{Pid: os.Getpid()}
+	return writeProcessState(stateFile, &startedProcess{cmd: cmd}, false)
+}
+
+func TestStartDetachedProcess(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() {
+		execCommand = exec.Command
+	}()
+	withTmpDir(t, "detached", func(t *testing.T, dir string) {
+		configurator := NewDriversConfigurator(&LifecycleConfig{
+			ConfigDir: dir,
+			Version:   Latest,
+			Port:      DefaultPort,
+			Detach:    true,
+		})
+		assert.NoError(t, configurator.Start())
+		<-configurator.process.done
+		assert.Eventually(t, func() bool {
+			var logs bytes.Buffer
+			return configurator.Logs(&logs, LogsOptions{}) == nil && strings.Contains(logs.String(), "PASS")
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
+
+func TestSupervise(t *testing.T) {
+	execCommand = fakeExecCommand
+	defer func() {
+		execCommand = exec.Command
+	}()
+	withTmpDir(t, "supervise", func(t *testing.T, dir string) {
+		configurator := NewDriversConfigurator(&LifecycleConfig{
+			Quiet:     true,
+			ConfigDir: dir,
+			Version:   Latest,
+			Port:      DefaultPort,
+		})
+		stop := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- configurator.Supervise(stop)
+		}()
+		time.Sleep(500 * time.Millisecond)
+		close(stop)
+		assert.NoError(t, <-done)
+		assert.True(t, fileExists(configurator.getServiceLogPath(selenoidLogFileName)))
+		assert.False(t, fileExists(configurator.getStateFilePath(selenoidStateFileName)))
+	})
+}
+
+func TestReloadDriversNotRunning(t *testing.T) {
+	withTmpDir(t, "test-reload", func(t *testing.T, dir string) {
+		d := NewDriversConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true})
+		assert.Error(t, d.Reload())
+	})
+}
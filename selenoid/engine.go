@@ -0,0 +1,319 @@
+package selenoid
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	authconfig "github.com/docker/cli/cli/config"
+	dockercontext "github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+const (
+	EngineAuto   = "auto"
+	EngineDocker = "docker"
+	EnginePodman = "podman"
+
+	defaultContextName = "default"
+	unixSocketScheme   = "unix://"
+	sshScheme          = "ssh"
+	podmanBinary       = "/usr/bin/podman"
+	podmanSocket       = "/run/podman/podman.sock"
+	userRuntimeDir     = "/run/user/"
+	defaultServiceHost = "localhost"
+)
+
+// EngineOptions are engine settings from command line, they take precedence over environment variables
+type EngineOptions struct {
+	Name    string
+	Host    string
+	Context string
+}
+
+// Engine is a container engine with Docker compatible API
+type Engine struct {
+	Name    string
+	Host    string
+	Context string
+	// Socket is engine socket path on local host, empty for remote engines
+	Socket   string
+	endpoint dockercontext.Endpoint
+}
+
+func (e *Engine) Title() string {
+	if e.Name == EnginePodman {
+		return "Podman"
+	}
+	return "Docker"
+}
+
+// ResolveEngine chooses engine host without connecting to it
+func ResolveEngine(opts EngineOptions) (*Engine, error) {
+	switch opts.Name {
+	case "", EngineAuto, EngineDocker, EnginePodman:
+	default:
+		return nil, fmt.Errorf("unsupported container engine: %s", opts.Name)
+	}
+	var candidates []*Engine
+	switch {
+	case opts.Host != "":
+		candidates = []*Engine{newEngine(EngineAuto, opts.Host)}
+	case opts.Context != "":
+		engine, err := dockerContextEngine(opts.Context)
+		if err != nil {
+			return nil, err
+		}
+		candidates = []*Engine{engine}
+	case opts.Name == "" || opts.Name == EngineAuto:
+		engines, err := dockerEngines()
+		if err != nil {
+			return nil, err
+		}
+		candidates = engines
+		if os.Getenv(client.EnvOverrideHost) == "" {
+			candidates = append(candidates, podmanEngines()...)
+		}
+	case opts.Name == EngineDocker:
+		engines, err := dockerEngines()
+		if err != nil {
+			return nil, err
+		}
+		candidates = engines
+	case opts.Name == EnginePodman:
+		candidates = podmanEngines()
+	}
+	// When no socket exists client reports connection error to the first candidate
+	ret := candidates[0]
+	for _, engine := range candidates {
+		if engine.Socket == "" || fileExists(engine.Socket) {
+			ret = engine
+			break
+		}
+	}
+	if opts.Name == EngineDocker || opts.Name == EnginePodman {
+		ret.Name = opts.Name
+	}
+	return ret, nil
+}
+
+func newEngine(name string, host string) *Engine {
+	e := &Engine{Name: name, Host: host}
+	e.endpoint.Host = host
+	if strings.HasPrefix(host, unixSocketScheme) {
+		e.Socket = strings.TrimPrefix(host, unixSocketScheme)
+	}
+	return e
+}
+
+// dockerEngines returns context from DOCKER_CONTEXT or DOCKER_HOST only when one of them is set and otherwise
+// current Docker context, rootful and rootless sockets. Podman can also listen on these sockets, so engine name
+// is determined after connecting
+func dockerEngines() ([]*Engine, error) {
+	name := currentDockerContext()
+	if name != "" && os.Getenv("DOCKER_CONTEXT") != "" {
+		engine, err := dockerContextEngine(name)
+		if err != nil {
+			return nil, err
+		}
+		return []*Engine{engine}, nil
+	}
+	if host := os.Getenv(client.EnvOverrideHost); host != "" {
+		return []*Engine{newEngine(EngineAuto, host)}, nil
+	}
+	var ret []*Engine
+	if name != "" {
+		engine, err := dockerContextEngine(name)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, engine)
+	}
+	ret = append(ret, newEngine(EngineAuto, client.DefaultDockerHost))
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		ret = append(ret, newEngine(EngineAuto, unixSocketScheme+filepath.Join(dir, "docker.sock")))
+	}
+	return ret, nil
+}
+
+func podmanEngines() []*Engine {
+	var ret []*Engine
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		ret = append(ret, newEngine(EnginePodman, host))
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		ret = append(ret, newEngine(EnginePodman, unixSocketScheme+filepath.Join(dir, "podman", "podman.sock")))
+	}
+	return append(ret, newEngine(EnginePodman, unixSocketScheme+podmanSocket))
+}
+
+// currentDockerContext returns Docker CLI context chosen with DOCKER_CONTEXT or docker context use command
+func currentDockerContext() string {
+	name := os.Getenv("DOCKER_CONTEXT")
+	if name == "" {
+		configFile, err := authconfig.Load("")
+		if err != nil {
+			return ""
+		}
+		name = configFile.CurrentContext
+	}
+	if name == defaultContextName {
+		return ""
+	}
+	return name
+}
+
+// dockerContextEngine loads Docker endpoint and its TLS material from Docker CLI context store
+func dockerContextEngine(name string) (*Engine, error) {
+	if name == defaultContextName {
+		return newEngine(EngineAuto, client.DefaultDockerHost), nil
+	}
+	s := store.New(authconfig.ContextStoreDir(), store.NewConfig(nil,
+		store.EndpointTypeGetter(dockercontext.DockerEndpoint, func() interface{} { return &dockercontext.EndpointMeta{} }),
+	))
+	meta, err := s.GetMetadata(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Docker context %s: %v", name, err)
+	}
+	endpointMeta, err := dockercontext.EndpointFromContext(meta)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Docker context %s: %v", name, err)
+	}
+	endpoint, err := dockercontext.WithTLSData(s, name, endpointMeta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS data of Docker context %s: %v", name, err)
+	}
+	engine := newEngine(EngineAuto, endpoint.Host)
+	engine.Context = name
+	engine.endpoint = endpoint
+	return engine, nil
+}
+
+// clientOpts configure client with engine host, TLS and SSH connection settings.
+// Context endpoint has its own TLS settings, so DOCKER_CERT_PATH and DOCKER_TLS_VERIFY are used only without context
+func (e *Engine) clientOpts() ([]client.Opt, error) {
+	opts, err := e.endpoint.ClientOpts()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s endpoint %s: %v", e.Title(), e.Host, err)
+	}
+	if e.Context != "" {
+		return opts, nil
+	}
+	return append([]client.Opt{client.FromEnv}, opts...), nil
+}
+
+// serviceHost returns host where ports of started containers are published
+func (e *Engine) serviceHost() string {
+	u, err := url.Parse(e.Host)
+	if err != nil || u.Scheme == "unix" || u.Scheme == "npipe" || u.Hostname() == "" {
+		return defaultServiceHost
+	}
+	return u.Hostname()
+}
+
+func isEngineAvailable(engine *Engine) bool {
+	opts, err := engine.clientOpts()
+	if err != nil {
+		return false
+	}
+	cl, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return false
+	}
+	defer cl.Close()
+	_, err = cl.Ping(context.Background())
+	return err == nil
+}
+
+// detectEngineName asks engine whether it is Podman when it was not chosen explicitly
+func detectEngineName(engine *Engine, docker *client.Client) {
+	if engine.Name != EngineAuto {
+		return
+	}
+	engine.Name = EngineDocker
+	v, err := docker.ServerVersion(context.Background())
+	if err != nil {
+		return
+	}
+	for _, component := range v.Components {
+		if strings.Contains(strings.ToLower(component.Name), EnginePodman) {
+			engine.Name = EnginePodman
+			return
+		}
+	}
+}
+
+// engineSocket returns engine socket path on the host where engine runs, for remote engines it is taken
+// from SSH endpoint path (e.g. ssh://user@host/run/user/1000/podman/podman.sock) or default engine location
+func (e *Engine) engineSocket() string {
+	if e.Socket != "" {
+		return e.Socket
+	}
+	if u, err := url.Parse(e.Host); err == nil && u.Scheme == sshScheme && u.Path != "" {
+		return u.Path
+	}
+	if e.Name == EnginePodman {
+		return podmanSocket
+	}
+	return dockerSocket
+}
+
+// rootless checks that engine socket belongs to user session, such engine is managed by user systemd instance
+func (e *Engine) rootless() bool {
+	if e.Socket == "" {
+		return false
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && strings.HasPrefix(e.Socket, filepath.Clean(dir)+string(filepath.Separator)) {
+		return true
+	}
+	return strings.HasPrefix(e.Socket, userRuntimeDir)
+}
+
+// socketVolume bind mounts engine socket to the path Selenoid expects it at
+func (e *Engine) socketVolume() string {
+	socket := e.engineSocket()
+	if e.Name == EnginePodman {
+		// Relabeling engine socket breaks Podman, label separation is disabled with security option instead
+		return fmt.Sprintf("%s:%s", socket, dockerSocket)
+	}
+	return fmt.Sprintf("%s:%s:Z", socket, dockerSocket)
+}
+
+func (e *Engine) securityOpt() []string {
+	if e.Name == EnginePodman {
+		return []string{"label=disable"}
+	}
+	return nil
+}
+
+// validUserNS checks user namespace mode, Podman supports more modes than Docker
+func (e *Engine) validUserNS(mode string) bool {
+	if e.Name == EnginePodman {
+		for _, prefix := range []string{"host", "private", "auto", "keep-id", "nomap", "ns:"} {
+			if strings.HasPrefix(mode, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return container.UsernsMode(mode).Valid()
+}
+
+func (e *Engine) binary() string {
+	if e.Name == EnginePodman {
+		return podmanBinary
+	}
+	return dockerBinary
+}
+
+func (e *Engine) systemdService() string {
+	if e.Name == EnginePodman {
+		return "podman.socket"
+	}
+	return "docker.service"
+}
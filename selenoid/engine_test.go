@@ -0,0 +1,214 @@
+package selenoid
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/docker/client"
+	assert "github.com/stretchr/testify/require"
+)
+
+func withDockerContext(t *testing.T, name string, host string, ca []byte, fn func(t *testing.T)) {
+	withTmpDir(t, "test-engine-context", func(t *testing.T, dir string) {
+		defer authconfig.SetDir(authconfig.Dir())
+		authconfig.SetDir(dir)
+		id := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+		metaDir := filepath.Join(dir, "contexts", "meta", id)
+		assert.NoError(t, os.MkdirAll(metaDir, os.ModePerm))
+		meta := fmt.Sprintf(`{"Name": "%s", "Metadata": {}, "Endpoints": {"docker": {"Host": "%s"}}}`, name, host)
+		assert.NoError(t, os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0644))
+		if ca != nil {
+			tlsDir := filepath.Join(dir, "contexts", "tls", id, "docker")
+			assert.NoError(t, os.MkdirAll(tlsDir, os.ModePerm))
+			assert.NoError(t, os.WriteFile(filepath.Join(tlsDir, "ca.pem"), ca, 0644))
+		}
+		fn(t)
+	})
+}
+
+func testCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestResolveEngineFromDockerContext(t *testing.T) {
+	withDockerContext(t, "remote", "tcp://remote:2376", testCA(t), func(t *testing.T) {
+		t.Setenv("DOCKER_HOST", "")
+		t.Setenv("DOCKER_CONTEXT", "remote")
+
+		engine, err := ResolveEngine(EngineOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "remote", engine.Context)
+		assert.Equal(t, "tcp://remote:2376", engine.Host)
+		assert.Empty(t, engine.Socket)
+		assert.NotNil(t, engine.endpoint.TLSData)
+		_, err = engine.clientOpts()
+		assert.NoError(t, err)
+	})
+}
+
+func TestContextFlagOverridesDockerHost(t *testing.T) {
+	withDockerContext(t, "remote", "tcp://remote:2376", nil, func(t *testing.T) {
+		engine, err := ResolveEngine(EngineOptions{Context: "remote"})
+		assert.NoError(t, err)
+		assert.Equal(t, "tcp://remote:2376", engine.Host)
+
+		engine, err = ResolveEngine(EngineOptions{Context: "remote", Host: "tcp://other:2375"})
+		assert.NoError(t, err)
+		assert.Equal(t, "tcp://other:2375", engine.Host)
+		assert.Empty(t, engine.Context)
+
+		_, err = ResolveEngine(EngineOptions{Context: "missing"})
+		assert.Error(t, err)
+	})
+}
+
+func TestDockerContextOverridesDockerHost(t *testing.T) {
+	withDockerContext(t, "remote", "tcp://remote:2375", nil, func(t *testing.T) {
+		t.Setenv("DOCKER_HOST", "tcp://other:2375")
+		t.Setenv("DOCKER_CONTEXT", "remote")
+		t.Setenv("DOCKER_TLS_VERIFY", "1")
+		t.Setenv("DOCKER_CERT_PATH", "/path/to/missing/certs")
+
+		engine, err := ResolveEngine(EngineOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "remote", engine.Context)
+		assert.Equal(t, "tcp://remote:2375", engine.Host)
+		opts, err := engine.clientOpts()
+		assert.NoError(t, err)
+		cl, err := client.NewClientWithOpts(opts...)
+		assert.NoError(t, err)
+		defer cl.Close()
+		assert.Equal(t, "tcp://remote:2375", cl.DaemonHost())
+
+		t.Setenv("DOCKER_CONTEXT", "")
+		engine, err = ResolveEngine(EngineOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "tcp://other:2375", engine.Host)
+		opts, err = engine.clientOpts()
+		assert.NoError(t, err)
+		_, err = client.NewClientWithOpts(opts...)
+		assert.Error(t, err)
+	})
+}
+
+func TestServiceHost(t *testing.T) {
+	assert.Equal(t, "localhost", newEngine(EngineDocker, "unix:///var/run/docker.sock").serviceHost())
+	assert.Equal(t, "localhost", newEngine(EngineDocker, "npipe:////./pipe/docker_engine").serviceHost())
+	assert.Equal(t, "remote", newEngine(EngineDocker, "tcp://remote:2376").serviceHost())
+	assert.Equal(t, "remote", newEngine(EngineDocker, "ssh://user@remote").serviceHost())
+	assert.Equal(t, "::1", newEngine(EngineDocker, "tcp://[::1]:2375").serviceHost())
+}
+
+func TestResolvePodmanEngine(t *testing.T) {
+	withTmpDir(t, "test-engine-podman", func(t *testing.T, dir string) {
+		socket := filepath.Join(dir, "podman", "podman.sock")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(socket), os.ModePerm))
+		assert.NoError(t, os.WriteFile(socket, nil, 0644))
+		t.Setenv("XDG_RUNTIME_DIR", dir)
+		t.Setenv("CONTAINER_HOST", "")
+
+		engine, err := ResolveEngine(EngineOptions{Name: EnginePodman})
+		assert.NoError(t, err)
+		assert.Equal(t, EnginePodman, engine.Name)
+		assert.Equal(t, "unix://"+socket, engine.Host)
+		assert.Equal(t, socket, engine.Socket)
+		assert.Equal(t, socket+":"+dockerSocket, engine.socketVolume())
+		assert.Equal(t, []string{"label=disable"}, engine.securityOpt())
+		assert.True(t, engine.validUserNS("keep-id"))
+		assert.False(t, engine.validUserNS("unknown"))
+		assert.Equal(t, podmanBinary, engine.binary())
+	})
+}
+
+func TestEngineSocket(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/tmp/runtime")
+	for _, tc := range []struct {
+		engine   *Engine
+		socket   string
+		rootless bool
+	}{
+		{newEngine(EngineDocker, "tcp://remote:2376"), dockerSocket, false},
+		{newEngine(EngineDocker, "npipe:////./pipe/docker_engine"), dockerSocket, false},
+		{newEngine(EnginePodman, "tcp://remote:8080"), podmanSocket, false},
+		{newEngine(EnginePodman, "ssh://user@remote:22/run/user/1000/podman/podman.sock"), "/run/user/1000/podman/podman.sock", false},
+		{newEngine(EnginePodman, "unix:///run/user/1000/podman/podman.sock"), "/run/user/1000/podman/podman.sock", true},
+		{newEngine(EngineDocker, "unix:///tmp/runtime/docker.sock"), "/tmp/runtime/docker.sock", true},
+		{newEngine(EngineDocker, "unix:///var/run/docker.sock"), "/var/run/docker.sock", false},
+	} {
+		assert.Equal(t, tc.socket, tc.engine.engineSocket(), tc.engine.Host)
+		assert.Equal(t, tc.rootless, tc.engine.rootless(), tc.engine.Host)
+	}
+	assert.Equal(t, "/var/run/docker.sock:"+dockerSocket+":Z", newEngine(EngineDocker, "tcp://remote:2376").socketVolume())
+}
+
+func TestResolveForcedDockerEngine(t *testing.T) {
+	engine, err := ResolveEngine(EngineOptions{Name: EngineDocker})
+	assert.NoError(t, err)
+	assert.Equal(t, EngineDocker, engine.Name)
+	assert.Equal(t, os.Getenv("DOCKER_HOST"), engine.Host)
+	assert.Empty(t, engine.securityOpt())
+	assert.True(t, engine.validUserNS("host"))
+	assert.False(t, engine.validUserNS("keep-id"))
+
+	_, err = ResolveEngine(EngineOptions{Name: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestDetectPodman(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"Version": "4.9.3", "ApiVersion": "1.41", "Components": [{"Name": "Podman Engine", "Version": "4.9.3"}]}`)
+	}))
+	defer srv.Close()
+	cl, err := client.NewClientWithOpts(client.WithHost("tcp://" + hostPort(srv.URL)))
+	assert.NoError(t, err)
+	defer cl.Close()
+
+	engine := &Engine{Name: EngineAuto, Host: "tcp://" + hostPort(srv.URL)}
+	detectEngineName(engine, cl)
+	assert.Equal(t, EnginePodman, engine.Name)
+	assert.Equal(t, "Podman", engine.Title())
+}
+
+func TestNegotiateAPIVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.41")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv("DOCKER_API_VERSION", "")
+
+	var determined string
+	noop := func(string) {}
+	cl, err := createCompatibleDockerClient(newEngine(EngineAuto, "tcp://"+hostPort(srv.URL)), noop, func(v string) { determined = v }, noop)
+	assert.NoError(t, err)
+	defer cl.Close()
+	assert.Equal(t, "1.41", determined)
+	assert.Equal(t, "1.41", cl.ClientVersion())
+	assert.Empty(t, os.Getenv("DOCKER_API_VERSION"))
+}
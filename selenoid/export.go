@@ -0,0 +1,369 @@
+package selenoid
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ExportCompose   = "compose"
+	ExportK8s       = "k8s"
+	ExportDockerRun = "docker-run"
+
+	videoRecorderServiceName = "video-recorder"
+	videoProfile             = "video"
+)
+
+type ExportOptions struct {
+	Format    string
+	UIPort    int
+	UIVersion string
+}
+
+// exportedDeployment contains the same containers settings as start commands use
+type exportedDeployment struct {
+	selenoid           *exportedContainer
+	ui                 *exportedContainer
+	videoRecorderImage string
+}
+
+type exportedContainer struct {
+	*containerConfig
+	image string
+	env   []string
+}
+
+func (c *DockerConfigurator) Export(w io.Writer, opts ExportOptions) error {
+	d := c.exportedDeployment(opts)
+	switch opts.Format {
+	case ExportCompose:
+		return d.renderCompose(w)
+	case ExportK8s:
+		return d.renderK8s(w)
+	case ExportDockerRun:
+		return d.renderDockerRun(w)
+	}
+	return fmt.Errorf("unsupported export format: %s", opts.Format)
+}
+
+func (c *DockerConfigurator) exportedDeployment(opts ExportOptions) *exportedDeployment {
+	selenoidCfg := c.selenoidContainerConfig(c.exportImage(selenoidImage, c.Version))
+	// Arguments and environment variables are meant for Selenoid only
+	ui := *c
+	ui.Args, ui.Env, ui.Port = "", nil, opts.UIPort
+	uiCfg := ui.selenoidUIContainerConfig(c.exportImage(selenoidUIImage, opts.UIVersion), c.selenoidUri())
+	d := &exportedDeployment{
+		selenoid: c.exportedContainer(selenoidCfg),
+		ui:       c.exportedContainer(uiCfg),
+	}
+	if isVideoRecordingSupported(c.Logger, c.Version) {
+		d.videoRecorderImage = c.getFullyQualifiedImageRef(videoRecorderImage)
+	}
+	return d
+}
+
+func (c *DockerConfigurator) exportedContainer(cfg *containerConfig) *exportedContainer {
+	return &exportedContainer{containerConfig: cfg, image: cfg.Image.RepoTags[0], env: c.exportedEnv(cfg)}
+}
+
+// exportImage uses downloaded image and falls back to image reference that would be pulled
+func (c *DockerConfigurator) exportImage(name string, version string) *image.Summary {
+	if img := c.getImage(name, version); img != nil {
+		return img
+	}
+	if version == "" {
+		version = Latest
+	}
+	return &image.Summary{RepoTags: []string{imageWithTag(c.getFullyQualifiedImageRef(name), version)}}
+}
+
+type composeFile struct {
+	Services map[string]*composeService `yaml:"services"`
+	Networks map[string]*composeNetwork `yaml:"networks"`
+}
+
+type composeService struct {
+	Image         string         `yaml:"image"`
+	ContainerName string         `yaml:"container_name,omitempty"`
+	Hostname      string         `yaml:"hostname,omitempty"`
+	Restart       string         `yaml:"restart,omitempty"`
+	Profiles      []string       `yaml:"profiles,omitempty"`
+	DependsOn     []string       `yaml:"depends_on,omitempty"`
+	Networks      []string       `yaml:"networks,omitempty"`
+	Ports         []quotedString `yaml:"ports,omitempty"`
+	Volumes       []string       `yaml:"volumes,omitempty"`
+	Environment   []string       `yaml:"environment,omitempty"`
+	UsernsMode    string         `yaml:"userns_mode,omitempty"`
+	SecurityOpt   []string       `yaml:"security_opt,omitempty"`
+	Command       []string       `yaml:"command,omitempty"`
+}
+
+// quotedString prevents YAML parsers from reading port mappings like 80:80 as numbers
+type quotedString string
+
+func (s quotedString) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Style: yaml.DoubleQuotedStyle, Value: string(s)}, nil
+}
+
+type composeNetwork struct {
+	Name string `yaml:"name"`
+}
+
+func (d *exportedDeployment) renderCompose(w io.Writer) error {
+	network := d.selenoid.Network
+	f := &composeFile{
+		Services: map[string]*composeService{
+			selenoidContainerName:   d.selenoid.composeService(),
+			selenoidUIContainerName: d.ui.composeService(),
+		},
+		// Selenoid starts browsers in this network by name, so Compose must not prefix it with project name
+		Networks: map[string]*composeNetwork{network: {Name: network}},
+	}
+	f.Services[selenoidUIContainerName].DependsOn = []string{selenoidContainerName}
+	if d.videoRecorderImage != "" {
+		// Video recorder containers are started by Selenoid, the service only allows to pull the image
+		f.Services[videoRecorderServiceName] = &composeService{
+			Image:    d.videoRecorderImage,
+			Profiles: []string{videoProfile},
+		}
+	}
+	return encodeYaml(w, f)
+}
+
+func (c *exportedContainer) composeService() *composeService {
+	s := &composeService{
+		Image:         c.image,
+		ContainerName: c.Name,
+		Hostname:      "localhost",
+		Restart:       "always",
+		Networks:      []string{c.Network},
+		Volumes:       c.Volumes,
+		Environment:   c.env,
+		UsernsMode:    c.UserNS,
+		SecurityOpt:   c.SecurityOpt,
+		Command:       c.Cmd,
+	}
+	if c.HostPort > 0 {
+		s.Ports = []quotedString{quotedString(fmt.Sprintf("%d:%d", c.HostPort, c.ServicePort))}
+	}
+	return s
+}
+
+type k8sObject struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   k8sMetadata `yaml:"metadata"`
+	Spec       interface{} `yaml:"spec"`
+}
+
+type k8sMetadata struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+type k8sDeploymentSpec struct {
+	Replicas int                `yaml:"replicas"`
+	Selector k8sSelector        `yaml:"selector"`
+	Template k8sPodTemplateSpec `yaml:"template"`
+}
+
+type k8sSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type k8sPodTemplateSpec struct {
+	Metadata k8sMetadata `yaml:"metadata"`
+	Spec     k8sPodSpec  `yaml:"spec"`
+}
+
+type k8sPodSpec struct {
+	HostNetwork bool           `yaml:"hostNetwork,omitempty"`
+	Containers  []k8sContainer `yaml:"containers"`
+	Volumes     []k8sVolume    `yaml:"volumes,omitempty"`
+}
+
+type k8sContainer struct {
+	Name         string           `yaml:"name"`
+	Image        string           `yaml:"image"`
+	Args         []string         `yaml:"args,omitempty"`
+	Ports        []k8sPort        `yaml:"ports,omitempty"`
+	Env          []k8sEnvVar      `yaml:"env,omitempty"`
+	VolumeMounts []k8sVolumeMount `yaml:"volumeMounts,omitempty"`
+}
+
+type k8sPort struct {
+	ContainerPort int `yaml:"containerPort"`
+}
+
+type k8sEnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type k8sVolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+type k8sVolume struct {
+	Name     string      `yaml:"name"`
+	HostPath k8sHostPath `yaml:"hostPath"`
+}
+
+type k8sHostPath struct {
+	Path string `yaml:"path"`
+}
+
+type k8sServiceSpec struct {
+	Type     string            `yaml:"type"`
+	Selector map[string]string `yaml:"selector"`
+	Ports    []k8sServicePort  `yaml:"ports"`
+}
+
+type k8sServicePort struct {
+	Port       int `yaml:"port"`
+	TargetPort int `yaml:"targetPort"`
+	NodePort   int `yaml:"nodePort,omitempty"`
+}
+
+// renderK8s uses host Docker through mounted socket, so Selenoid runs in host network to reach browser containers
+func (d *exportedDeployment) renderK8s(w io.Writer) error {
+	var objects []interface{}
+	for _, c := range []*exportedContainer{d.selenoid, d.ui} {
+		objects = append(objects, c.k8sDeployment(c == d.selenoid), c.k8sService())
+	}
+	return encodeYaml(w, objects...)
+}
+
+func (c *exportedContainer) k8sLabels() map[string]string {
+	return map[string]string{"app": c.Name}
+}
+
+func (c *exportedContainer) k8sDeployment(hostNetwork bool) *k8sObject {
+	ctr := k8sContainer{
+		Name:  c.Name,
+		Image: c.image,
+		Args:  c.Cmd,
+		Ports: []k8sPort{{ContainerPort: c.ServicePort}},
+	}
+	for _, e := range c.env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 {
+			ctr.Env = append(ctr.Env, k8sEnvVar{Name: kv[0], Value: kv[1]})
+		}
+	}
+	var volumes []k8sVolume
+	for i, v := range c.Volumes {
+		parts := strings.Split(v, ":")
+		if len(parts) < 2 {
+			continue
+		}
+		name := "volume-" + strconv.Itoa(i)
+		volumes = append(volumes, k8sVolume{Name: name, HostPath: k8sHostPath{Path: parts[0]}})
+		readOnly := len(parts) > 2 && contains(strings.Split(parts[2], ","), "ro")
+		ctr.VolumeMounts = append(ctr.VolumeMounts, k8sVolumeMount{Name: name, MountPath: parts[1], ReadOnly: readOnly})
+	}
+	return &k8sObject{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Metadata:   k8sMetadata{Name: c.Name, Labels: c.k8sLabels()},
+		Spec: k8sDeploymentSpec{
+			Replicas: 1,
+			Selector: k8sSelector{MatchLabels: c.k8sLabels()},
+			Template: k8sPodTemplateSpec{
+				Metadata: k8sMetadata{Name: c.Name, Labels: c.k8sLabels()},
+				Spec: k8sPodSpec{
+					HostNetwork: hostNetwork,
+					Containers:  []k8sContainer{ctr},
+					Volumes:     volumes,
+				},
+			},
+		},
+	}
+}
+
+func (c *exportedContainer) k8sService() *k8sObject {
+	return &k8sObject{
+		APIVersion: "v1",
+		Kind:       "Service",
+		Metadata:   k8sMetadata{Name: c.Name, Labels: c.k8sLabels()},
+		Spec: k8sServiceSpec{
+			Type:     "ClusterIP",
+			Selector: c.k8sLabels(),
+			Ports:    []k8sServicePort{{Port: c.HostPort, TargetPort: c.ServicePort}},
+		},
+	}
+}
+
+// encodeYaml writes every value as a separate YAML document
+func encodeYaml(w io.Writer, values ...interface{}) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, v := range values {
+		err := enc.Encode(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal yaml: %v", err)
+		}
+	}
+	return enc.Close()
+}
+
+func (d *exportedDeployment) renderDockerRun(w io.Writer) error {
+	lines := [][]string{{"docker", "network", "create", d.selenoid.Network}}
+	if d.videoRecorderImage != "" {
+		lines = append(lines, []string{"docker", "pull", d.videoRecorderImage})
+	}
+	for _, c := range []*exportedContainer{d.selenoid, d.ui} {
+		lines = append(lines, append([]string{"docker"}, c.dockerRunArgs("-d", "--restart", "always")...))
+	}
+	for _, line := range lines {
+		_, err := fmt.Fprintln(w, shellCommandLine(line))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerRunArgs returns "docker run" arguments equivalent to container created by startContainer
+func (c *exportedContainer) dockerRunArgs(flags ...string) []string {
+	args := append([]string{"run"}, flags...)
+	args = append(args, "--name", c.Name, "--hostname", "localhost")
+	if c.Network != "" {
+		args = append(args, "--network", c.Network)
+	}
+	if c.HostPort > 0 && c.ServicePort > 0 {
+		args = append(args, "-p", fmt.Sprintf("%d:%d", c.HostPort, c.ServicePort))
+	}
+	for _, v := range c.Volumes {
+		args = append(args, "-v", v)
+	}
+	for _, e := range c.env {
+		args = append(args, "-e", e)
+	}
+	if c.UserNS != "" {
+		args = append(args, "--userns", c.UserNS)
+	}
+	for _, opt := range c.SecurityOpt {
+		args = append(args, "--security-opt", opt)
+	}
+	args = append(args, c.image)
+	return append(args, c.Cmd...)
+}
+
+func shellCommandLine(args []string) string {
+	var ret []string
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`;&|<>()*?!#~") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		ret = append(ret, arg)
+	}
+	return strings.Join(ret, " ")
+}
@@ -0,0 +1,33 @@
+package selenoid
+
+import (
+	"bytes"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	c, err := NewDockerConfigurator(&LifecycleConfig{
+		ConfigDir:   goldenConfigDir,
+		RegistryUrl: mockDockerServer.URL,
+		Version:     Latest,
+		Port:        DefaultPort,
+		Args:        "-limit 5",
+	})
+	assert.NoError(t, err)
+	for format, golden := range map[string]string{
+		ExportCompose:   "docker-compose.yml",
+		ExportK8s:       "k8s.yml",
+		ExportDockerRun: "docker-run.sh",
+	} {
+		var buf bytes.Buffer
+		assert.NoError(t, c.Export(&buf, ExportOptions{Format: format, UIPort: UIDefaultPort, UIVersion: Latest}))
+		assertGoldenOutput(t, buf.String(), golden)
+	}
+	assert.Error(t, c.Export(&bytes.Buffer{}, ExportOptions{Format: "unknown"}))
+}
+
+func TestShellCommandLine(t *testing.T) {
+	assert.Equal(t, `docker run -e 'KEY=a b' -e 'OTHER=it'\''s' '' image`, shellCommandLine([]string{"docker", "run", "-e", "KEY=a b", "-e", "OTHER=it's", "", "image"}))
+}
@@ -1,13 +1,12 @@
 package selenoid
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"time"
 
-	"github.com/docker/docker/client"
 	"github.com/fatih/color"
 )
 
@@ -18,29 +17,49 @@ type LifecycleConfig struct {
 	GracefulTimeout time.Duration
 	ConfigDir       string
 	Browsers        string
-	BrowserEnv      string
+	BrowserEnv      []string
 	Download        bool
 	Args            string
-	Env             string
+	Env             []string
 	Version         string
 	Port            int
 	DisableLogs     bool
+	WaitTimeout     time.Duration
+	Instance        string
+	Retry           RetryPolicy
 
 	// Docker specific
-	LastVersions int
-	RegistryUrl  string
-	BrowsersJson string
-	ShmSize      int
-	Tmpfs        int
-	VNC          bool
-	UserNS       string
+	LastVersions       int
+	TagFilter          string
+	IncludePrereleases bool
+	RegistryUrl        string
+	RegistryMirrors    []string
+	BrowsersJson       string
+	Catalog            string
+	Parallel           int
+	PinDigests         bool
+	ShmSize            int
+	Tmpfs              int
+	VNC                bool
+	UserNS             string
+	Volumes            []string
+	Engine             string
+	DockerHost         string
+	Context            string
 
 	// Drivers specific
 	UseDrivers     bool
 	DriversInfoUrl string
+	CacheDir       string
 	GithubBaseUrl  string
 	OS             string
 	Arch           string
+	Adopt          bool
+	Detach         bool
+}
+
+func (c *LifecycleConfig) engineOptions() EngineOptions {
+	return EngineOptions{Name: c.Engine, Host: c.DockerHost, Context: c.Context}
 }
 
 type Lifecycle struct {
@@ -52,10 +71,27 @@ type Lifecycle struct {
 	downloadable Downloadable
 	configurable Configurable
 	runnable     Runnable
+	logsProvider LogsProvider
+	supervisor   Supervisor
+	systemdAware SystemdUnitProvider
+	exporter     Exporter
+	verifier     DigestVerifier
+	upgradable   Upgradable
+	archiver     ImageArchiver
+	pruner       ImagePruner
+	cleaner      Cleaner
+	lister       BrowsersLister
+	editor       BrowsersEditor
+	reloadable   Reloadable
 	closer       io.Closer
+	// serviceHost is where readiness of started services is checked, engine host for remote engines
+	serviceHost string
 }
 
 func NewLifecycle(config *LifecycleConfig) (*Lifecycle, error) {
+	if err := ValidateInstanceName(config.Instance); err != nil {
+		return nil, err
+	}
 	lc := Lifecycle{
 		Logger:    Logger{Quiet: config.Quiet},
 		Forceable: Forceable{Force: config.Force},
@@ -69,23 +105,48 @@ func NewLifecycle(config *LifecycleConfig) (*Lifecycle, error) {
 		lc.downloadable = driversCfg
 		lc.configurable = driversCfg
 		lc.runnable = driversCfg
+		lc.logsProvider = driversCfg
+		lc.supervisor = driversCfg
+		lc.systemdAware = driversCfg
+		lc.upgradable = driversCfg
+		lc.reloadable = driversCfg
 		lc.closer = driversCfg
 		return &lc, nil
 	}
-	if !isDockerAvailable() {
-		return nil, errors.New("can not access Docker: make sure you have Docker installed and current user has access permissions")
+	engine, err := ResolveEngine(config.engineOptions())
+	if err != nil {
+		return nil, err
+	}
+	if !isEngineAvailable(engine) {
+		return nil, fmt.Errorf("can not access %s at %s: make sure you have it installed and current user has access permissions", engine.Title(), engine.Host)
 	}
-	lc.Titlef("Using %v", color.BlueString("Docker"))
 	dockerCfg, err := NewDockerConfigurator(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Docker support: %v", err)
 	}
+	if engine.Context != "" {
+		lc.Titlef("Using %v from context %v", color.BlueString(dockerCfg.engine.Title()), color.BlueString(engine.Context))
+	} else {
+		lc.Titlef("Using %v", color.BlueString(dockerCfg.engine.Title()))
+	}
 	lc.argsAware = dockerCfg
 	lc.statusAware = dockerCfg
 	lc.downloadable = dockerCfg
 	lc.configurable = dockerCfg
 	lc.runnable = dockerCfg
+	lc.logsProvider = dockerCfg
+	lc.systemdAware = dockerCfg
+	lc.exporter = dockerCfg
+	lc.verifier = dockerCfg
+	lc.upgradable = dockerCfg
+	lc.archiver = dockerCfg
+	lc.pruner = dockerCfg
+	lc.cleaner = dockerCfg
+	lc.lister = dockerCfg
+	lc.editor = dockerCfg
+	lc.reloadable = dockerCfg
 	lc.closer = dockerCfg
+	lc.serviceHost = dockerCfg.engine.serviceHost()
 	return &lc, nil
 }
 
@@ -95,12 +156,12 @@ func (l *Lifecycle) Close() {
 	}
 }
 
-func (l *Lifecycle) Status() {
-	l.statusAware.Status()
+func (l *Lifecycle) Status() *Status {
+	return l.statusAware.Status()
 }
 
-func (l *Lifecycle) UIStatus() {
-	l.statusAware.UIStatus()
+func (l *Lifecycle) UIStatus() *Status {
+	return l.statusAware.UIStatus()
 }
 
 func (l *Lifecycle) Download() error {
@@ -178,6 +239,10 @@ func (l *Lifecycle) Start() error {
 
 			l.Titlef("Starting Selenoid...")
 			err := l.runnable.Start()
+			if err != nil {
+				return err
+			}
+			err = l.waitUntilReady("Selenoid", selenoidReadinessUrls(l.readinessHost(), l.Config.Port), l.logsProvider.Logs)
 			if err == nil {
 				l.Titlef("Successfully started Selenoid")
 			}
@@ -218,6 +283,10 @@ func (l *Lifecycle) StartUI() error {
 			}
 			l.Titlef("Starting Selenoid UI...")
 			err := l.runnable.StartUI()
+			if err != nil {
+				return err
+			}
+			err = l.waitUntilReady("Selenoid UI", selenoidUIReadinessUrls(l.readinessHost(), l.Config.Port), l.logsProvider.UILogs)
 			if err == nil {
 				l.Titlef("Successfully started Selenoid UI")
 			}
@@ -252,13 +321,133 @@ func (l *Lifecycle) StopUI() error {
 	return err
 }
 
-func isDockerAvailable() bool {
-	cl, err := client.NewClientWithOpts(client.FromEnv)
+func (l *Lifecycle) Supervise(stop <-chan struct{}) error {
+	if l.supervisor == nil {
+		return errors.New("supervising is supported in drivers mode only, Docker restarts containers itself")
+	}
+	return chain([]func() error{
+		func() error {
+			return l.Configure()
+		},
+		func() error {
+			if l.runnable.IsRunning() {
+				if !l.Force {
+					return errors.New("Selenoid is already running")
+				}
+				l.Titlef("Stopping previous Selenoid instance...")
+				err := l.Stop()
+				if err != nil {
+					return fmt.Errorf("failed to stop previous Selenoid instance: %v", err)
+				}
+			}
+			l.Titlef("Supervising Selenoid...")
+			return l.supervisor.Supervise(stop)
+		},
+	})
+}
+
+// SystemdUnit renders unit file from flags and existing configuration path without downloading or configuring anything
+func (l *Lifecycle) SystemdUnit() (*SystemdUnit, error) {
+	if !l.configurable.IsConfigured() {
+		l.Pointf("Selenoid is not configured yet, run configure command before starting the unit")
+	}
+	return l.systemdAware.SystemdUnit()
+}
+
+func (l *Lifecycle) UISystemdUnit() (*SystemdUnit, error) {
+	if !l.downloadable.IsUIDownloaded() {
+		l.Pointf("Selenoid UI is not downloaded yet, run download command before starting the unit")
+	}
+	return l.systemdAware.UISystemdUnit()
+}
+
+func (l *Lifecycle) Export(w io.Writer, opts ExportOptions) error {
+	if l.exporter == nil {
+		return errors.New("export is supported in Docker mode only")
+	}
+	return l.exporter.Export(w, opts)
+}
+
+func (l *Lifecycle) VerifyDigests() ([]ImageDigestStatus, error) {
+	if l.verifier == nil {
+		return nil, errors.New("digest verification is supported in Docker mode only")
+	}
+	return l.verifier.VerifyDigests()
+}
+
+func (l *Lifecycle) Prune(opts PruneOptions) (*PruneReport, error) {
+	if l.pruner == nil {
+		return nil, errors.New("pruning images is supported in Docker mode only")
+	}
+	return l.pruner.Prune(opts)
+}
+
+func (l *Lifecycle) ListBrowsers(opts BrowsersListOptions) ([]BrowserListing, error) {
+	if l.lister == nil {
+		return nil, errors.New("listing browser versions is supported in Docker mode only")
+	}
+	return l.lister.ListBrowsers(opts)
+}
+
+func (l *Lifecycle) AddBrowsers(opts BrowsersEditOptions) error {
+	if l.editor == nil {
+		return errors.New("editing browsers.json is supported in Docker mode only")
+	}
+	return l.editor.AddBrowsers(opts)
+}
+
+func (l *Lifecycle) RemoveBrowsers(opts BrowsersEditOptions) error {
+	if l.editor == nil {
+		return errors.New("editing browsers.json is supported in Docker mode only")
+	}
+	return l.editor.RemoveBrowsers(opts)
+}
+
+// Reload makes running Selenoid apply browsers.json, stopped Selenoid will read it on start
+func (l *Lifecycle) Reload() error {
+	if !l.runnable.IsRunning() {
+		l.Titlef("Selenoid is not running, configuration will be applied on start")
+		return nil
+	}
+	l.Titlef("Reloading Selenoid configuration...")
+	err := l.reloadable.Reload()
+	if err == nil {
+		l.Titlef("Successfully reloaded Selenoid configuration")
+	}
+	return err
+}
+
+func (l *Lifecycle) Logs(w io.Writer, opts LogsOptions) error {
+	return l.logsProvider.Logs(w, opts)
+}
+
+func (l *Lifecycle) UILogs(w io.Writer, opts LogsOptions) error {
+	return l.logsProvider.UILogs(w, opts)
+}
+
+const diagnosticsLogLines = 50
+
+func (l *Lifecycle) readinessHost() string {
+	if l.serviceHost == "" {
+		return defaultServiceHost
+	}
+	return l.serviceHost
+}
+
+func (l *Lifecycle) waitUntilReady(service string, urls []string, logs func(io.Writer, LogsOptions) error) error {
+	if l.Config.WaitTimeout <= 0 {
+		return nil
+	}
+	l.Titlef("Waiting for %s to become ready...", service)
+	err := waitUntilReady(urls, l.Config.WaitTimeout)
 	if err != nil {
-		return false
+		l.Errorf("%s did not become ready, last logs:", service)
+		if logsErr := logs(os.Stderr, LogsOptions{Tail: diagnosticsLogLines}); logsErr != nil {
+			l.Errorf("Failed to read %s logs: %v", service, logsErr)
+		}
+		return fmt.Errorf("%s is %v", service, err)
 	}
-	_, err = cl.Ping(context.Background())
-	return err == nil
+	return nil
 }
 
 func chain(steps []func() error) error {
@@ -1,10 +1,13 @@
 package selenoid
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
 
 	assert "github.com/stretchr/testify/require"
@@ -15,14 +18,20 @@ type MockStrategy struct {
 	isRunning      bool
 	isUIDownloaded bool
 	isUIRunning    bool
+	isConfigured   bool
+	reloads        int
 }
 
-func (ms *MockStrategy) Status() {
-	//Does nothing
+func (ms *MockStrategy) Status() *Status {
+	status := &Status{Service: "Selenoid"}
+	status.setState(ms.isRunning, ms.isConfigured)
+	return status
 }
 
-func (ms *MockStrategy) UIStatus() {
-	//Does nothing
+func (ms *MockStrategy) UIStatus() *Status {
+	status := &Status{Service: "Selenoid UI"}
+	status.setState(ms.isRunning, ms.isDownloaded)
+	return status
 }
 
 func (ms *MockStrategy) IsDownloaded() bool {
@@ -42,7 +51,7 @@ func (ms *MockStrategy) DownloadUI() (string, error) {
 }
 
 func (ms *MockStrategy) IsConfigured() bool {
-	return false
+	return ms.isConfigured
 }
 
 func (ms *MockStrategy) Configure() (*SelenoidConfig, error) {
@@ -77,16 +86,33 @@ func (ms *MockStrategy) Stop() error {
 	return nil
 }
 
+func (ms *MockStrategy) Reload() error {
+	ms.reloads++
+	return nil
+}
+
 func (ms *MockStrategy) StopUI() error {
 	return nil
 }
 
+func (ms *MockStrategy) Logs(w io.Writer, _ LogsOptions) error {
+	_, err := fmt.Fprintln(w, "Some logs...")
+	return err
+}
+
+func (ms *MockStrategy) UILogs(w io.Writer, _ LogsOptions) error {
+	_, err := fmt.Fprintln(w, "Some UI logs...")
+	return err
+}
+
 func (ms *MockStrategy) Close() error {
 	return nil
 }
 
 func TestDockerUnavailable(t *testing.T) {
-	assert.False(t, isDockerAvailable())
+	engine, err := ResolveEngine(EngineOptions{})
+	assert.NoError(t, err)
+	assert.False(t, isEngineAvailable(engine))
 }
 
 func TestDockerAvailable(t *testing.T) {
@@ -98,7 +124,9 @@ func TestDockerAvailable(t *testing.T) {
 	_ = os.Setenv("DOCKER_HOST", "tcp://"+hostPort(mockDockerServer.URL))
 	defer os.Unsetenv("DOCKER_HOST")
 
-	assert.True(t, isDockerAvailable())
+	engine, err := ResolveEngine(EngineOptions{})
+	assert.NoError(t, err)
+	assert.True(t, isEngineAvailable(engine))
 }
 
 func hostPort(input string) string {
@@ -134,10 +162,22 @@ func createTestLifecycle(strategy MockStrategy) Lifecycle {
 		downloadable: &strategy,
 		configurable: &strategy,
 		runnable:     &strategy,
+		logsProvider: &strategy,
+		reloadable:   &strategy,
 		closer:       &strategy,
 	}
 }
 
+func TestReload(t *testing.T) {
+	strategy := &MockStrategy{}
+	lc := Lifecycle{Config: &LifecycleConfig{}, runnable: strategy, reloadable: strategy}
+	assert.NoError(t, lc.Reload())
+	assert.Equal(t, 0, strategy.reloads)
+	strategy.isRunning = true
+	assert.NoError(t, lc.Reload())
+	assert.Equal(t, 1, strategy.reloads)
+}
+
 func TestUILifecycle(t *testing.T) {
 	strategy := MockStrategy{}
 	lc := createTestLifecycle(strategy)
@@ -151,3 +191,25 @@ func TestUILifecycle(t *testing.T) {
 	strategy.isRunning = false
 	assert.NoError(t, lc.StopUI())
 }
+
+func TestStatusState(t *testing.T) {
+	strategy := &MockStrategy{}
+	lc := createPlanTestLifecycle(strategy)
+	assert.Equal(t, StateNotConfigured, lc.Status().State)
+	strategy.isConfigured = true
+	assert.Equal(t, StateStopped, lc.Status().State)
+	strategy.isRunning = true
+	assert.True(t, lc.Status().IsRunning())
+	lc.PrintStatus(lc.Status())
+}
+
+func TestReadBrowsersStatus(t *testing.T) {
+	withTmpDir(t, "test-status", func(t *testing.T, dir string) {
+		configPath := getSelenoidConfigPath(dir)
+		_ = os.WriteFile(configPath, []byte(`{"firefox": {"default": "46.0", "versions": {"46.0": {}, "45.0": {}}}}`), 0644)
+		assert.Equal(t, map[string]BrowserVersions{
+			"firefox": {Default: "46.0", Versions: []string{"45.0", "46.0"}},
+		}, readBrowsersStatus(configPath))
+		assert.Nil(t, readBrowsersStatus(filepath.Join(dir, "missing.json")))
+	})
+}
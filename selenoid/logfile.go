@@ -0,0 +1,260 @@
+package selenoid
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// LogWriterCommand is a hidden selenoid subcommand copying standard input to rotated log file
+	LogWriterCommand = "write-log"
+
+	selenoidLogFileName   = "selenoid.log"
+	selenoidUILogFileName = "selenoid-ui.log"
+
+	serviceLogMaxSize = 10 * 1024 * 1024
+	serviceLogBackups = 5
+
+	logFollowInterval = 500 * time.Millisecond
+
+	// Selenoid and Selenoid UI use standard Go logger format
+	logTimestampLayout = "2006/01/02 15:04:05"
+)
+
+// rotatingWriter writes to a file and renames it to file.1, file.2 and so on when it becomes too big
+type rotatingWriter struct {
+	lock    sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+func newRotatingWriter(path string, maxSize int64, backups int) (*rotatingWriter, error) {
+	w := &rotatingWriter{path: path, maxSize: maxSize, backups: backups}
+	err := w.open()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) open() error {
+	f, err := openLogFile(w.path)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to access log file: %v", err)
+	}
+	w.file = f
+	w.size = fi.Size()
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		err := w.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) rotate() error {
+	err := w.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close log file: %v", err)
+	}
+	err = rotateLogFiles(w.path, w.backups)
+	if err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *rotatingWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.file.Close()
+}
+
+func rotateLogFiles(path string, backups int) error {
+	_ = os.Remove(backupLogFileName(path, backups))
+	for i := backups - 1; i > 0; i-- {
+		err := os.Rename(backupLogFileName(path, i), backupLogFileName(path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file: %v", err)
+		}
+	}
+	err := os.Rename(path, backupLogFileName(path, 1))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %v", err)
+	}
+	return nil
+}
+
+func backupLogFileName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+func openLogFile(path string) (*os.File, error) {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logs directory: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %v", err)
+	}
+	return f, nil
+}
+
+// WriteLog copies output of detached process to log file rotating it while process is running
+func WriteLog(path string, r io.Reader) error {
+	w, err := newRotatingWriter(path, serviceLogMaxSize, serviceLogBackups)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	_, err = io.Copy(w, r)
+	if err != nil {
+		return fmt.Errorf("failed to write log file: %v", err)
+	}
+	return nil
+}
+
+// startLogWriter starts detached cm process writing data from returned pipe to log file, so that log is rotated
+// after cm exits. Log writer stops when detached process closes its output
+func startLogWriter(path string) (*os.File, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cm executable: %v", err)
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log pipe: %v", err)
+	}
+	defer r.Close()
+	cmd := execCommand(self, "selenoid", LogWriterCommand, path)
+	cmd.Stdin = r
+	cmd.SysProcAttr = detachedProcAttr()
+	err = cmd.Start()
+	if err != nil {
+		_ = w.Close()
+		return nil, fmt.Errorf("failed to start log writer: %v", err)
+	}
+	_ = cmd.Process.Release()
+	return w, nil
+}
+
+func tailLogFile(path string, w io.Writer, opts LogsOptions) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read log file: %v", err)
+	}
+	_, err = w.Write(lastLines(linesSince(data, opts.Since), opts.Tail))
+	if err != nil || !opts.Follow {
+		return err
+	}
+	return followLogFile(path, w, int64(len(data)))
+}
+
+func lastLines(data []byte, n int) []byte {
+	if n <= 0 {
+		return data
+	}
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for i := 0; i < n; i++ {
+		pos := bytes.LastIndexByte(data[:end], '\n')
+		if pos < 0 {
+			return data
+		}
+		end = pos
+	}
+	return data[end+1:]
+}
+
+// linesSince skips lines logged before since, lines without timestamp belong to previous line
+func linesSince(data []byte, since time.Time) []byte {
+	if since.IsZero() {
+		return data
+	}
+	var ret []byte
+	keep := false
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if len(line) >= len(logTimestampLayout) {
+			t, err := time.ParseInLocation(logTimestampLayout, string(line[:len(logTimestampLayout)]), time.Local)
+			if err == nil {
+				keep = !t.Before(since)
+			}
+		}
+		if keep {
+			ret = append(ret, line...)
+		}
+	}
+	return ret
+}
+
+// ParseSince accepts either relative duration (e.g. 10m) or RFC 3339 timestamp
+func ParseSince(since string) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	d, err := time.ParseDuration(since)
+	if err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use duration like 10m or timestamp like 2006-01-02T15:04:05Z", since)
+	}
+	return t, nil
+}
+
+func followLogFile(path string, w io.Writer, offset int64) error {
+	for {
+		time.Sleep(logFollowInterval)
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if fi.Size() < offset {
+			// File was rotated
+			offset = 0
+		}
+		if fi.Size() == offset {
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		_, err = f.Seek(offset, io.SeekStart)
+		if err == nil {
+			var n int64
+			n, err = io.Copy(w, f)
+			offset += n
+		}
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read log file: %v", err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package selenoid
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestRotatingWriter(t *testing.T) {
+	withTmpDir(t, "logs", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "logs", selenoidLogFileName)
+		w, err := newRotatingWriter(path, 10, 2)
+		assert.NoError(t, err)
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err = w.Write([]byte(line))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		assertFileContents(t, path, "fourth\n")
+		assertFileContents(t, backupLogFileName(path, 1), "third\n")
+		assertFileContents(t, backupLogFileName(path, 2), "second\n")
+		assert.False(t, fileExists(backupLogFileName(path, 3)))
+	})
+}
+
+func TestWriteLog(t *testing.T) {
+	withTmpDir(t, "logs", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "logs", selenoidLogFileName)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, make([]byte, serviceLogMaxSize), 0644))
+		assert.NoError(t, WriteLog(path, strings.NewReader("started\n")))
+		assertFileContents(t, path, "started\n")
+		assert.True(t, fileExists(backupLogFileName(path, 1)))
+	})
+}
+
+func TestLastLines(t *testing.T) {
+	data := []byte("one\ntwo\nthree\n")
+	assert.Equal(t, "one\ntwo\nthree\n", string(lastLines(data, 0)))
+	assert.Equal(t, "three\n", string(lastLines(data, 1)))
+	assert.Equal(t, "two\nthree\n", string(lastLines(data, 2)))
+	assert.Equal(t, "one\ntwo\nthree\n", string(lastLines(data, 10)))
+	assert.Equal(t, "two\nthree", string(lastLines([]byte("one\ntwo\nthree"), 2)))
+}
+
+func TestTailLogFile(t *testing.T) {
+	withTmpDir(t, "logs", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, selenoidLogFileName)
+		var buf bytes.Buffer
+		assert.Error(t, tailLogFile(path, &buf, LogsOptions{}))
+		assert.NoError(t, os.WriteFile(path, []byte("one\ntwo\n"), 0644))
+		assert.NoError(t, tailLogFile(path, &buf, LogsOptions{Tail: 1}))
+		assert.Equal(t, "two\n", buf.String())
+	})
+}
+
+func assertFileContents(t *testing.T, path string, contents string) {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, contents, string(data))
+}
+
+func TestLinesSince(t *testing.T) {
+	data := []byte("2024/01/01 10:00:00 first\ncontinued\n2024/01/01 11:00:00 second\ncontinued\n")
+	assert.Equal(t, data, linesSince(data, time.Time{}))
+	since := time.Date(2024, 1, 1, 10, 30, 0, 0, time.Local)
+	assert.Equal(t, "2024/01/01 11:00:00 second\ncontinued\n", string(linesSince(data, since)))
+}
+
+func TestParseSince(t *testing.T) {
+	since, err := ParseSince("")
+	assert.NoError(t, err)
+	assert.True(t, since.IsZero())
+	since, err = ParseSince("10m")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-10*time.Minute), since, time.Second)
+	since, err = ParseSince("2024-01-01T10:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), since)
+	_, err = ParseSince("yesterday")
+	assert.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package selenoid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultLastVersions = 2
+
+// Manifest describes desired Selenoid and Selenoid UI installation on this host
+type Manifest struct {
+	Selenoid *SelenoidSpec `json:"selenoid,omitempty" yaml:"selenoid,omitempty"`
+	UI       *UISpec       `json:"ui,omitempty" yaml:"ui,omitempty"`
+}
+
+type SelenoidSpec struct {
+	Instance     string              `json:"instance,omitempty" yaml:"instance,omitempty"`
+	Version      string              `json:"version,omitempty" yaml:"version,omitempty"`
+	UseDrivers   bool                `json:"useDrivers,omitempty" yaml:"useDrivers,omitempty"`
+	ConfigDir    string              `json:"configDir,omitempty" yaml:"configDir,omitempty"`
+	Port         int                 `json:"port,omitempty" yaml:"port,omitempty"`
+	Registry     string              `json:"registry,omitempty" yaml:"registry,omitempty"`
+	Browsers     map[string][]string `json:"browsers,omitempty" yaml:"browsers,omitempty"`
+	BrowsersJson string              `json:"browsersJson,omitempty" yaml:"browsersJson,omitempty"`
+	Catalog      string              `json:"catalog,omitempty" yaml:"catalog,omitempty"`
+	Parallel     int                 `json:"parallel,omitempty" yaml:"parallel,omitempty"`
+	PinDigests   bool                `json:"pinDigests,omitempty" yaml:"pinDigests,omitempty"`
+	LastVersions *int                `json:"lastVersions,omitempty" yaml:"lastVersions,omitempty"`
+	BrowserEnv   []string            `json:"browserEnv,omitempty" yaml:"browserEnv,omitempty"`
+	ShmSize      int                 `json:"shmSize,omitempty" yaml:"shmSize,omitempty"`
+	Tmpfs        int                 `json:"tmpfs,omitempty" yaml:"tmpfs,omitempty"`
+	Limit        int                 `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Args         []string            `json:"args,omitempty" yaml:"args,omitempty"`
+	Env          []string            `json:"env,omitempty" yaml:"env,omitempty"`
+	Volumes      []string            `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	UserNS       string              `json:"userNS,omitempty" yaml:"userNS,omitempty"`
+	DisableLogs  bool                `json:"disableLogs,omitempty" yaml:"disableLogs,omitempty"`
+}
+
+type UISpec struct {
+	Instance   string   `json:"instance,omitempty" yaml:"instance,omitempty"`
+	Version    string   `json:"version,omitempty" yaml:"version,omitempty"`
+	UseDrivers bool     `json:"useDrivers,omitempty" yaml:"useDrivers,omitempty"`
+	ConfigDir  string   `json:"configDir,omitempty" yaml:"configDir,omitempty"`
+	Port       int      `json:"port,omitempty" yaml:"port,omitempty"`
+	Registry   string   `json:"registry,omitempty" yaml:"registry,omitempty"`
+	Args       []string `json:"args,omitempty" yaml:"args,omitempty"`
+	Env        []string `json:"env,omitempty" yaml:"env,omitempty"`
+	UserNS     string   `json:"userNS,omitempty" yaml:"userNS,omitempty"`
+}
+
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %v", path, err)
+	}
+	var m Manifest
+	err = unmarshalFile(path, data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %v", path, err)
+	}
+	if m.Selenoid == nil && m.UI == nil {
+		return nil, errors.New("manifest should contain selenoid or ui section")
+	}
+	if m.Selenoid != nil {
+		m.Selenoid.setDefaults()
+	}
+	if m.UI != nil {
+		m.UI.setDefaults()
+	}
+	return &m, nil
+}
+
+// unmarshalFile parses JSON files as JSON and everything else as YAML
+func unmarshalFile(path string, data []byte, v interface{}) error {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return json.Unmarshal(data, v)
+	}
+	return yaml.Unmarshal(data, v)
+}
+
+func (s *SelenoidSpec) setDefaults() {
+	if s.Version == "" {
+		s.Version = Latest
+	}
+	if s.Port == 0 {
+		s.Port = DefaultPort
+	}
+	if s.Registry == "" {
+		s.Registry = DefaultRegistryUrl
+	}
+	s.ConfigDir = expandConfigDir(s.ConfigDir, GetSelenoidInstanceConfigDir(s.Instance))
+	if s.LastVersions == nil {
+		lastVersions := defaultLastVersions
+		s.LastVersions = &lastVersions
+	}
+}
+
+func (s *UISpec) setDefaults() {
+	if s.Version == "" {
+		s.Version = Latest
+	}
+	if s.Port == 0 {
+		s.Port = UIDefaultPort
+	}
+	if s.Registry == "" {
+		s.Registry = DefaultRegistryUrl
+	}
+	s.ConfigDir = expandConfigDir(s.ConfigDir, GetSelenoidUIInstanceConfigDir(s.Instance))
+}
+
+func expandConfigDir(dir string, defaultDir string) string {
+	if dir == "" {
+		return defaultDir
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		return joinPaths(getHomeDir(), []string{strings.TrimPrefix(dir, "~")})
+	}
+	ap, _ := filepath.Abs(dir)
+	return ap
+}
+
+func (s *SelenoidSpec) LifecycleConfig() *LifecycleConfig {
+	args := append([]string{}, s.Args...)
+	if s.Limit > 0 && !contains(args, "-limit") {
+		args = append(args, "-limit", strconv.Itoa(s.Limit))
+	}
+	return &LifecycleConfig{
+		Instance:     s.Instance,
+		Retry:        DefaultRetryPolicy(),
+		ConfigDir:    s.ConfigDir,
+		Browsers:     s.requestedBrowsers(),
+		BrowserEnv:   s.BrowserEnv,
+		Download:     true,
+		Args:         strings.Join(args, " "),
+		Env:          s.Env,
+		Version:      s.Version,
+		Port:         s.Port,
+		DisableLogs:  s.DisableLogs,
+		LastVersions: *s.LastVersions,
+		RegistryUrl:  s.Registry,
+		BrowsersJson: s.BrowsersJson,
+		Catalog:      s.Catalog,
+		Parallel:     s.Parallel,
+		PinDigests:   s.PinDigests,
+		ShmSize:      s.ShmSize,
+		Tmpfs:        s.Tmpfs,
+		UserNS:       s.UserNS,
+		Volumes:      s.Volumes,
+
+		UseDrivers:     s.UseDrivers,
+		DriversInfoUrl: DefaultDriversInfoURL,
+		CacheDir:       GetCacheDir(),
+		OS:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+	}
+}
+
+// requestedBrowsers converts browsers map to the format understood by parseRequestedBrowsers
+func (s *SelenoidSpec) requestedBrowsers() string {
+	var names []string
+	for name := range s.Browsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sections []string
+	for _, name := range names {
+		constraints := s.Browsers[name]
+		if len(constraints) == 0 {
+			sections = append(sections, name)
+			continue
+		}
+		for _, constraint := range constraints {
+			sections = append(sections, name+colon+constraint)
+		}
+	}
+	return strings.Join(sections, semicolon)
+}
+
+func (s *UISpec) LifecycleConfig() *LifecycleConfig {
+	return &LifecycleConfig{
+		Instance:    s.Instance,
+		Retry:       DefaultRetryPolicy(),
+		ConfigDir:   s.ConfigDir,
+		Download:    true,
+		Args:        strings.Join(s.Args, " "),
+		Env:         s.Env,
+		Version:     s.Version,
+		Port:        s.Port,
+		RegistryUrl: s.Registry,
+		UserNS:      s.UserNS,
+
+		UseDrivers: s.UseDrivers,
+		CacheDir:   GetCacheDir(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
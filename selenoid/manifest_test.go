@@ -0,0 +1,77 @@
+package selenoid
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+const testManifest = `
+selenoid:
+  version: 1.11.2
+  port: 4445
+  configDir: /opt/selenoid
+  browsers:
+    firefox: [">=120.0"]
+    chrome: []
+  lastVersions: 3
+  limit: 10
+  args: ["-timeout", "1m"]
+  env: ["KEY1=value1", "KEY2=value with spaces"]
+  volumes: ["/data:/data"]
+ui:
+  port: 8081
+`
+
+func TestLoadManifest(t *testing.T) {
+	withTmpDir(t, "test-manifest", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "cm.yaml")
+		_ = os.WriteFile(path, []byte(testManifest), 0644)
+		m, err := LoadManifest(path)
+		assert.NoError(t, err)
+
+		assert.NotNil(t, m.Selenoid)
+		cfg := m.Selenoid.LifecycleConfig()
+		assert.Equal(t, "1.11.2", cfg.Version)
+		assert.Equal(t, 4445, cfg.Port)
+		assert.Equal(t, "/opt/selenoid", cfg.ConfigDir)
+		assert.Equal(t, "chrome;firefox:>=120.0", cfg.Browsers)
+		assert.Equal(t, 3, cfg.LastVersions)
+		assert.Equal(t, "-timeout 1m -limit 10", cfg.Args)
+		assert.Equal(t, []string{"KEY1=value1", "KEY2=value with spaces"}, cfg.Env)
+		assert.Equal(t, []string{"/data:/data"}, cfg.Volumes)
+		assert.Equal(t, DefaultRegistryUrl, cfg.RegistryUrl)
+
+		assert.NotNil(t, m.UI)
+		uiCfg := m.UI.LifecycleConfig()
+		assert.Equal(t, Latest, uiCfg.Version)
+		assert.Equal(t, 8081, uiCfg.Port)
+		assert.Equal(t, GetSelenoidUIConfigDir(), uiCfg.ConfigDir)
+	})
+}
+
+func TestLoadJSONManifest(t *testing.T) {
+	withTmpDir(t, "test-manifest", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "cm.json")
+		_ = os.WriteFile(path, []byte(`{"selenoid": {"useDrivers": true}}`), 0644)
+		m, err := LoadManifest(path)
+		assert.NoError(t, err)
+		assert.Nil(t, m.UI)
+		cfg := m.Selenoid.LifecycleConfig()
+		assert.True(t, cfg.UseDrivers)
+		assert.Equal(t, DefaultPort, cfg.Port)
+		assert.Equal(t, defaultLastVersions, cfg.LastVersions)
+		assert.Equal(t, GetSelenoidConfigDir(), cfg.ConfigDir)
+	})
+}
+
+func TestLoadEmptyManifest(t *testing.T) {
+	withTmpDir(t, "test-manifest", func(t *testing.T, dir string) {
+		path := filepath.Join(dir, "cm.yaml")
+		_ = os.WriteFile(path, []byte(`version: 1`), 0644)
+		_, err := LoadManifest(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,184 @@
+package selenoid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/go-units"
+	"github.com/fatih/color"
+)
+
+type PruneOptions struct {
+	DryRun bool
+	Keep   int
+}
+
+// PrunedImage is a browser image tag not referenced by browsers.json
+type PrunedImage struct {
+	Image string `json:"image"`
+	ID    string `json:"id"`
+	Size  int64  `json:"size"`
+}
+
+type PruneReport struct {
+	DryRun    bool          `json:"dryRun,omitempty"`
+	Removed   []PrunedImage `json:"removed,omitempty"`
+	Reclaimed int64         `json:"reclaimed"`
+}
+
+// referencedImages returns image references and digests used in browsers.json and in its copies saved by upgrade,
+// so that rollback to any snapshot does not need to pull removed images again
+func (c *DockerConfigurator) referencedImages() (map[string]struct{}, error) {
+	ret := make(map[string]struct{})
+	err := addReferencedImages(ret, getSelenoidConfigPath(c.ConfigDir))
+	if err != nil {
+		return nil, err
+	}
+	snapshots, _ := filepath.Glob(getSelenoidConfigPath(filepath.Join(c.ConfigDir, snapshotsDirName, "*")))
+	for _, configPath := range snapshots {
+		err := addReferencedImages(ret, configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func addReferencedImages(referenced map[string]struct{}, configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read browsers.json: %v", err)
+	}
+	var cfg SelenoidConfig
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to parse browsers.json from %s: %v", configPath, err)
+	}
+	for _, ref := range browserImages(&cfg) {
+		pieces := strings.SplitN(ref, digestSeparator, 2)
+		if len(pieces) == 2 {
+			referenced[normalizedRepositoryName(pieces[0])+digestSeparator+pieces[1]] = struct{}{}
+			continue
+		}
+		referenced[normalizedRepositoryName(ref)+colon+imageTag(ref)] = struct{}{}
+	}
+	return nil
+}
+
+// imageTag returns image reference tag or latest when tag is omitted
+func imageTag(ref string) string {
+	repo := repositoryName(ref)
+	if len(ref) > len(repo)+1 && ref[len(repo)] == ':' {
+		return ref[len(repo)+1:]
+	}
+	return Latest
+}
+
+func isReferencedImage(img image.Summary, repo string, tag string, referenced map[string]struct{}) bool {
+	if _, ok := referenced[repo+colon+tag]; ok {
+		return true
+	}
+	for _, repoDigest := range img.RepoDigests {
+		pieces := strings.SplitN(repoDigest, digestSeparator, 2)
+		if len(pieces) != 2 {
+			continue
+		}
+		if _, ok := referenced[normalizedRepositoryName(pieces[0])+digestSeparator+pieces[1]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Prune removes local images of managed browsers not referenced by browsers.json
+func (c *DockerConfigurator) Prune(opts PruneOptions) (*PruneReport, error) {
+	referenced, err := c.referencedImages()
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := c.loadCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load browsers catalog: %v", err)
+	}
+	repositories := make(map[string]struct{})
+	for _, browser := range c.getBrowsersToIterate(catalog, parseRequestedBrowsers(&c.Logger, c.Browsers)) {
+		repositories[normalizedRepositoryName(c.getFullyQualifiedImageRef(browser.Image))] = struct{}{}
+	}
+	ctx := context.Background()
+	images, err := c.docker.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %v", err)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Created > images[j].Created
+	})
+
+	report := &PruneReport{DryRun: opts.DryRun}
+	kept := make(map[string]int)
+	for _, img := range images {
+		var unused []string
+		otherTags := false
+		for _, ref := range img.RepoTags {
+			repo := normalizedRepositoryName(ref)
+			if _, ok := repositories[repo]; !ok {
+				otherTags = true
+				continue
+			}
+			if isReferencedImage(img, repo, imageTag(ref), referenced) {
+				otherTags = true
+				continue
+			}
+			if kept[repo] < opts.Keep {
+				kept[repo]++
+				otherTags = true
+				continue
+			}
+			unused = append(unused, ref)
+		}
+		for i, ref := range unused {
+			pruned := PrunedImage{Image: ref, ID: img.ID}
+			// Space is reclaimed only when the last tag of the image is removed
+			if !otherTags && i == len(unused)-1 {
+				pruned.Size = img.Size
+			}
+			if !opts.DryRun {
+				c.Pointf("Removing image %v", color.BlueString(ref))
+				_, err := c.docker.ImageRemove(ctx, ref, image.RemoveOptions{PruneChildren: true})
+				if err != nil {
+					c.Errorf("Failed to remove image %s: %v", ref, err)
+					// Image keeps this tag, so no space is reclaimed
+					otherTags = true
+					continue
+				}
+			}
+			report.Removed = append(report.Removed, pruned)
+			report.Reclaimed += pruned.Size
+		}
+	}
+	return report, nil
+}
+
+func (l *Lifecycle) PrintPruneReport(report *PruneReport) {
+	action := "Removed"
+	if report.DryRun {
+		action = "Would remove"
+	}
+	for _, pruned := range report.Removed {
+		l.Pointf("%s image %v", action, color.BlueString(pruned.Image))
+	}
+	if len(report.Removed) == 0 {
+		l.Titlef("No unused browser images found")
+		return
+	}
+	if report.DryRun {
+		l.Titlef("Would reclaim %v", color.GreenString(units.HumanSize(float64(report.Reclaimed))))
+		return
+	}
+	l.Titlef("Reclaimed %v", color.GreenString(units.HumanSize(float64(report.Reclaimed))))
+}
@@ -0,0 +1,99 @@
+package selenoid
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func mockPruneDockerServer(removed *requestRecorder) *httptest.Server {
+	return mockDockerServerWith(func(w http.ResponseWriter, r *http.Request) bool {
+		switch {
+		case r.URL.Path == "/v1.29/images/json":
+			_, _ = fmt.Fprintf(w, `[
+				{"Id": "sha256:1", "RepoTags": ["selenoid/firefox:46.0"], "Created": 100, "Size": 1000},
+				{"Id": "sha256:2", "RepoTags": ["selenoid/firefox:45.0"], "Created": 90, "Size": 100},
+				{"Id": "sha256:3", "RepoTags": ["selenoid/firefox:44.0"], "Created": 80, "Size": 10},
+				{"Id": "sha256:4", "RepoTags": ["selenoid/opera:33.0", "mycompany/opera:33.0"], "Created": 70, "Size": 1},
+				{"Id": "sha256:7", "RepoTags": ["selenoid/opera:32.0", "selenoid/opera:31.0"], "Created": 65, "Size": 5},
+				{"Id": "sha256:5", "RepoTags": ["selenoid/chrome:120.0"], "RepoDigests": ["selenoid/chrome@%s"], "Created": 60, "Size": 1},
+				{"Id": "sha256:6", "RepoTags": ["ubuntu:22.04"], "Created": 50, "Size": 1}
+			]`, testDigest("selenoid/chrome"))
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1.29/images/selenoid/opera:32.0":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprint(w, `{"message": "image is being used by running container"}`)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1.29/images/"):
+			removed.record(strings.TrimPrefix(r.URL.Path, "/v1.29/images/"))
+			_, _ = fmt.Fprint(w, `[{"Untagged": "image"}]`)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+func TestPrune(t *testing.T) {
+	var removed requestRecorder
+	srv := mockPruneDockerServer(&removed)
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	withTmpDir(t, "test-prune", func(t *testing.T, dir string) {
+		data := fmt.Sprintf(`{
+			"chrome": {"default": "120.0", "versions": {"120.0": {"image": "selenoid/chrome@%s"}}},
+			"firefox": {"default": "46.0", "versions": {"46.0": {"image": "selenoid/firefox:46.0"}}}
+		}`, testDigest("selenoid/chrome"))
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(data), 0644))
+		snapshotDir := filepath.Join(dir, snapshotsDirName, "20240101000000")
+		assert.NoError(t, os.MkdirAll(snapshotDir, os.ModePerm))
+		data = `{"firefox": {"default": "45.0", "versions": {"45.0": {"image": "selenoid/firefox:45.0"}}}}`
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(snapshotDir), []byte(data), 0644))
+
+		c, err := NewDockerConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true, Browsers: "chrome;firefox;opera"})
+		assert.NoError(t, err)
+		defer c.Close()
+
+		report, err := c.Prune(PruneOptions{DryRun: true})
+		assert.NoError(t, err)
+		assert.Empty(t, removed.recorded())
+		assert.Equal(t, &PruneReport{
+			DryRun: true,
+			Removed: []PrunedImage{
+				{Image: "selenoid/firefox:44.0", ID: "sha256:3", Size: 10},
+				{Image: "selenoid/opera:33.0", ID: "sha256:4"},
+				{Image: "selenoid/opera:32.0", ID: "sha256:7"},
+				{Image: "selenoid/opera:31.0", ID: "sha256:7", Size: 5},
+			},
+			Reclaimed: 15,
+		}, report)
+
+		report, err = c.Prune(PruneOptions{Keep: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, &PruneReport{
+			Removed: []PrunedImage{{Image: "selenoid/opera:31.0", ID: "sha256:7"}},
+		}, report)
+		assert.Equal(t, []string{"selenoid/opera:31.0"}, removed.recorded())
+	})
+}
+
+func TestPruneRequiresConfig(t *testing.T) {
+	withTmpDir(t, "test-prune-no-config", func(t *testing.T, dir string) {
+		c, err := NewDockerConfigurator(&LifecycleConfig{ConfigDir: dir, Quiet: true})
+		assert.NoError(t, err)
+		defer c.Close()
+		_, err = c.Prune(PruneOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "120.0", imageTag("selenoid/chrome:120.0"))
+	assert.Equal(t, Latest, imageTag("localhost:5000/selenoid/chrome"))
+	assert.Equal(t, "1.0", imageTag("localhost:5000/selenoid/chrome:1.0"))
+}
@@ -0,0 +1,186 @@
+package selenoid
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerokube/cm/render/rewriter"
+	"github.com/docker/go-units"
+	"github.com/fatih/color"
+	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	pullWaiting     = "Waiting"
+	pullInProgress  = "Pulling"
+	pullDone        = "Done"
+	pullFailed      = "Failed"
+	pullingFrom     = "Pulling from"
+	progressRefresh = 200 * time.Millisecond
+)
+
+// pullProgress renders progress of several concurrent image pulls as one block.
+// When output is not a terminal only layer status changes are printed line by line.
+type pullProgress struct {
+	lock     sync.Mutex
+	out      io.Writer
+	rewriter *rewriter.Rewriter
+	images   []*imageProgress
+	byRef    map[string]*imageProgress
+	started  time.Time
+	drawn    time.Time
+}
+
+type imageProgress struct {
+	ref    string
+	status string
+	err    error
+	layers []*layerProgress
+	byID   map[string]*layerProgress
+}
+
+type layerProgress struct {
+	id      string
+	status  string
+	current int64
+	total   int64
+}
+
+func newPullProgress(quiet bool, refs []string) *pullProgress {
+	if quiet {
+		return newPullProgressWriter(io.Discard, false, refs)
+	}
+	tty := isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+	return newPullProgressWriter(colorable.NewColorableStdout(), tty, refs)
+}
+
+func newPullProgressWriter(out io.Writer, tty bool, refs []string) *pullProgress {
+	p := &pullProgress{
+		out:     out,
+		byRef:   make(map[string]*imageProgress),
+		started: time.Now(),
+	}
+	if tty {
+		p.rewriter = rewriter.New(out)
+	}
+	for _, ref := range refs {
+		img := &imageProgress{ref: ref, status: pullWaiting, byID: make(map[string]*layerProgress)}
+		p.images = append(p.images, img)
+		p.byRef[ref] = img
+	}
+	return p
+}
+
+func (p *pullProgress) start(ref string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	img := p.byRef[ref]
+	img.status = pullInProgress
+	p.println("%s: %s", ref, pullInProgress)
+	p.draw(false)
+}
+
+func (p *pullProgress) update(ref string, msg *JSONMessage) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	img := p.byRef[ref]
+	if msg.ID == "" || strings.HasPrefix(msg.Status, pullingFrom) {
+		return
+	}
+	layer, ok := img.byID[msg.ID]
+	if !ok {
+		layer = &layerProgress{id: msg.ID}
+		img.layers = append(img.layers, layer)
+		img.byID[msg.ID] = layer
+	}
+	if msg.Progress != nil && msg.Progress.Total > 0 {
+		layer.current, layer.total = msg.Progress.Current, msg.Progress.Total
+	}
+	if isLayerComplete(msg.Status) && layer.total > 0 {
+		layer.current = layer.total
+	}
+	if layer.status != msg.Status {
+		layer.status = msg.Status
+		p.println("%s: [%s] %s", ref, layer.id, layer.status)
+	}
+	p.draw(false)
+}
+
+func isLayerComplete(status string) bool {
+	return status == "Download complete" || status == "Pull complete" || status == "Already exists"
+}
+
+func (p *pullProgress) finish(ref string, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	img := p.byRef[ref]
+	if err != nil {
+		img.status, img.err = pullFailed, err
+		p.println("%s: %s: %v", ref, pullFailed, err)
+	} else {
+		img.status = pullDone
+		p.println("%s: %s", ref, pullDone)
+	}
+	p.draw(true)
+}
+
+func (p *pullProgress) println(format string, v ...interface{}) {
+	if p.rewriter == nil {
+		_, _ = fmt.Fprintf(p.out, "\t"+format+"\n", v...)
+	}
+}
+
+func (p *pullProgress) draw(force bool) {
+	if p.rewriter == nil || (!force && time.Since(p.drawn) < progressRefresh) {
+		return
+	}
+	p.drawn = time.Now()
+	_, _ = io.WriteString(p.rewriter, p.render())
+	_ = p.rewriter.Flush()
+}
+
+func (p *pullProgress) render() string {
+	var b strings.Builder
+	var current, total int64
+	for _, img := range p.images {
+		for _, layer := range img.layers {
+			current += layer.current
+			total += layer.total
+		}
+		switch img.status {
+		case pullDone:
+			_, _ = fmt.Fprintf(&b, "\t%s: %s\n", img.ref, color.GreenString(img.status))
+			continue
+		case pullFailed:
+			_, _ = fmt.Fprintf(&b, "\t%s: %s\n", img.ref, color.RedString(img.status))
+			continue
+		}
+		_, _ = fmt.Fprintf(&b, "\t%s: %s\n", img.ref, img.status)
+		for _, layer := range img.layers {
+			if isLayerComplete(layer.status) {
+				continue
+			}
+			_, _ = fmt.Fprintf(&b, "\t\t[%s]: %s", layer.id, layer.status)
+			if layer.total > 0 {
+				_, _ = fmt.Fprintf(&b, " %s/%s", units.HumanSize(float64(layer.current)), units.HumanSize(float64(layer.total)))
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, _ = fmt.Fprintf(&b, "\tTotal: %s/%s%s\n", units.HumanSize(float64(current)), units.HumanSize(float64(total)), p.eta(current, total))
+	return b.String()
+}
+
+func (p *pullProgress) eta(current int64, total int64) string {
+	if current <= 0 || current >= total {
+		return ""
+	}
+	elapsed := time.Since(p.started)
+	left := time.Duration(float64(elapsed) * float64(total-current) / float64(current))
+	return fmt.Sprintf(", ETA %s", left.Round(time.Second))
+}
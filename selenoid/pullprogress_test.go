@@ -0,0 +1,55 @@
+package selenoid
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPullProgressLines(t *testing.T) {
+	var buf bytes.Buffer
+	p := newPullProgressWriter(&buf, false, []string{"selenoid/firefox:46.0", "selenoid/opera:44.0"})
+	p.start("selenoid/firefox:46.0")
+	p.update("selenoid/firefox:46.0", &JSONMessage{ID: "46.0", Status: "Pulling from selenoid/firefox"})
+	p.update("selenoid/firefox:46.0", &JSONMessage{ID: "a86cd3433934", Status: "Downloading", Progress: &JSONProgress{Current: 10, Total: 100}})
+	p.update("selenoid/firefox:46.0", &JSONMessage{ID: "a86cd3433934", Status: "Downloading", Progress: &JSONProgress{Current: 50, Total: 100}})
+	p.update("selenoid/firefox:46.0", &JSONMessage{ID: "a86cd3433934", Status: "Pull complete"})
+	p.finish("selenoid/firefox:46.0", nil)
+	p.start("selenoid/opera:44.0")
+	p.finish("selenoid/opera:44.0", errors.New("manifest unknown"))
+
+	assert.Equal(t, strings.Join([]string{
+		"\tselenoid/firefox:46.0: Pulling",
+		"\tselenoid/firefox:46.0: [a86cd3433934] Downloading",
+		"\tselenoid/firefox:46.0: [a86cd3433934] Pull complete",
+		"\tselenoid/firefox:46.0: Done",
+		"\tselenoid/opera:44.0: Pulling",
+		"\tselenoid/opera:44.0: Failed: manifest unknown",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestPullProgressRender(t *testing.T) {
+	p := newPullProgressWriter(&bytes.Buffer{}, false, []string{"selenoid/firefox:46.0", "selenoid/opera:44.0"})
+	p.start("selenoid/firefox:46.0")
+	p.update("selenoid/firefox:46.0", &JSONMessage{ID: "a86cd3433934", Status: "Downloading", Progress: &JSONProgress{Current: 1000, Total: 4000}})
+	p.update("selenoid/firefox:46.0", &JSONMessage{ID: "b86cd3433934", Status: "Download complete", Progress: &JSONProgress{Current: 500, Total: 1000}})
+	out := p.render()
+	assert.Contains(t, out, "\tselenoid/firefox:46.0: Pulling\n")
+	assert.Contains(t, out, "\t\t[a86cd3433934]: Downloading 1kB/4kB\n")
+	assert.NotContains(t, out, "b86cd3433934")
+	assert.Contains(t, out, "\tselenoid/opera:44.0: Waiting\n")
+	assert.Contains(t, out, "\tTotal: 2kB/5kB, ETA ")
+}
+
+func TestPullProgressEta(t *testing.T) {
+	p := newPullProgressWriter(&bytes.Buffer{}, false, nil)
+	p.started = time.Now().Add(-10 * time.Second)
+	assert.Equal(t, ", ETA 30s", p.eta(25, 100))
+	assert.Empty(t, p.eta(0, 100))
+	assert.Empty(t, p.eta(100, 100))
+}
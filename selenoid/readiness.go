@@ -0,0 +1,55 @@
+package selenoid
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const readinessInterval = 500 * time.Millisecond
+
+func selenoidReadinessUrls(host string, port int) []string {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	return []string{
+		fmt.Sprintf("http://%s/ping", address),
+		fmt.Sprintf("http://%s/status", address),
+	}
+}
+
+func selenoidUIReadinessUrls(host string, port int) []string {
+	return []string{
+		fmt.Sprintf("http://%s/status", net.JoinHostPort(host, strconv.Itoa(port))),
+	}
+}
+
+// waitUntilReady polls all urls until every one of them responds with 200 OK or timeout expires
+func waitUntilReady(urls []string, timeout time.Duration) error {
+	client := &http.Client{Timeout: readinessInterval * 2}
+	deadline := time.Now().Add(timeout)
+	for {
+		err := checkUrls(client, urls)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(readinessInterval).After(deadline) {
+			return fmt.Errorf("not ready after %v: %v", timeout, err)
+		}
+		time.Sleep(readinessInterval)
+	}
+}
+
+func checkUrls(client *http.Client, urls []string) error {
+	for _, u := range urls {
+		resp, err := client.Get(u)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s returned unexpected response code: %d", u, resp.StatusCode)
+		}
+	}
+	return nil
+}
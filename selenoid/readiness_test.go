@@ -0,0 +1,55 @@
+package selenoid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func readinessServer(failures int32) (*httptest.Server, int) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	p, _ := strconv.Atoi(srv.URL[len("http://127.0.0.1:"):])
+	return srv, p
+}
+
+func TestWaitUntilReady(t *testing.T) {
+	srv, p := readinessServer(2)
+	defer srv.Close()
+	assert.NoError(t, waitUntilReady(selenoidReadinessUrls(defaultServiceHost, p), 5*time.Second))
+}
+
+func TestReadinessUrls(t *testing.T) {
+	assert.Equal(t, []string{"http://localhost:4444/ping", "http://localhost:4444/status"}, selenoidReadinessUrls(defaultServiceHost, DefaultPort))
+	assert.Equal(t, []string{"http://[::1]:8080/status"}, selenoidUIReadinessUrls("::1", UIDefaultPort))
+}
+
+func TestWaitUntilReadyTimeout(t *testing.T) {
+	srv, p := readinessServer(1000)
+	defer srv.Close()
+	assert.Error(t, waitUntilReady(selenoidUIReadinessUrls(defaultServiceHost, p), time.Second))
+}
+
+func TestLifecycleStartWaitsForReadiness(t *testing.T) {
+	srv, p := readinessServer(1)
+	defer srv.Close()
+	lc := createPlanTestLifecycle(&MockStrategy{isDownloaded: true, isConfigured: true})
+	lc.Config = &LifecycleConfig{Port: p, WaitTimeout: 5 * time.Second}
+	assert.NoError(t, lc.Start())
+	assert.NoError(t, lc.StartUI())
+
+	srv.Close()
+	lc.Config.WaitTimeout = time.Second
+	assert.Error(t, lc.Start())
+}
@@ -0,0 +1,333 @@
+package selenoid
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/heroku/docker-registry-client/registry"
+)
+
+const (
+	dockerHubAuthKey = "https://index.docker.io/v1/"
+	tokenClientID    = "cm"
+)
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryTokenTransport implements registry token authentication with password, identity token or registry token.
+// Obtained tokens are reused for requests to the same repository until registry rejects them.
+type registryTokenTransport struct {
+	Transport http.RoundTripper
+	Auth      *configtypes.AuthConfig
+
+	lock   sync.Mutex
+	tokens map[string]string
+}
+
+func (t *registryTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Auth != nil && t.Auth.RegistryToken != "" {
+		return t.roundTripWithToken(req, t.Auth.RegistryToken)
+	}
+	scope := requestScope(req)
+	var resp *http.Response
+	var err error
+	if token := t.cachedToken(scope); token != "" {
+		resp, err = t.roundTripWithToken(req, token)
+	} else {
+		resp, err = t.Transport.RoundTrip(req)
+	}
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := bearerChallenge(resp.Header.Get("WWW-Authenticate"))
+	if challenge == nil {
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	token, err := t.token(req.Context(), challenge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain registry token: %v", err)
+	}
+	t.cacheToken(challenge["scope"], token)
+	return t.roundTripWithToken(req, token)
+}
+
+func (t *registryTokenTransport) roundTripWithToken(req *http.Request, token string) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.Transport.RoundTrip(req)
+}
+
+func (t *registryTokenTransport) cachedToken(scope string) string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.tokens[scope]
+}
+
+func (t *registryTokenTransport) cacheToken(scope string, token string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.tokens == nil {
+		t.tokens = make(map[string]string)
+	}
+	t.tokens[scope] = token
+}
+
+// requestScope returns token scope of registry request, e.g. repository:selenoid/chrome:pull for /v2/selenoid/chrome/tags/list
+func requestScope(req *http.Request) string {
+	name, ok := strings.CutPrefix(req.URL.Path, "/v2/")
+	if !ok {
+		return ""
+	}
+	for _, suffix := range []string{"/tags/", "/manifests/", "/blobs/"} {
+		if i := strings.LastIndex(name, suffix); i > 0 {
+			return "repository:" + name[:i] + ":pull"
+		}
+	}
+	return ""
+}
+
+// bearerChallenge parses WWW-Authenticate header like Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func bearerChallenge(header string) map[string]string {
+	scheme, params, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "bearer") {
+		return nil
+	}
+	ret := make(map[string]string)
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(params, -1) {
+		ret[strings.ToLower(match[1])] = match[2]
+	}
+	if ret["realm"] == "" {
+		return nil
+	}
+	return ret
+}
+
+func (t *registryTokenTransport) token(ctx context.Context, challenge map[string]string) (string, error) {
+	params := url.Values{}
+	for _, name := range []string{"service", "scope"} {
+		if challenge[name] != "" {
+			params.Set(name, challenge[name])
+		}
+	}
+	var req *http.Request
+	var err error
+	if t.Auth != nil && t.Auth.IdentityToken != "" {
+		params.Set("grant_type", "refresh_token")
+		params.Set("refresh_token", t.Auth.IdentityToken)
+		params.Set("client_id", tokenClientID)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, challenge["realm"], strings.NewReader(params.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, challenge["realm"], nil)
+		if err != nil {
+			return "", err
+		}
+		req.URL.RawQuery = params.Encode()
+		if t.Auth != nil && (t.Auth.Username != "" || t.Auth.Password != "") {
+			req.SetBasicAuth(t.Auth.Username, t.Auth.Password)
+		}
+	}
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token server %s returned status %d", challenge["realm"], resp.StatusCode)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("invalid token server response: %v", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("token server %s returned empty token", challenge["realm"])
+}
+
+// registryAuthConfig resolves registry credentials from Docker config file including credential helpers
+func (c *DockerConfigurator) registryAuthConfig(registryUrl string) *configtypes.AuthConfig {
+	if c.configFile == nil {
+		return nil
+	}
+	keys := []string{registryHostname(registryUrl)}
+	if registryUrl == DefaultRegistryUrl {
+		keys = append([]string{dockerHubAuthKey}, keys...)
+	}
+	for _, key := range keys {
+		cfg, err := c.configFile.GetAuthConfig(key)
+		if err != nil {
+			c.Errorf(`Failed to get credentials for "%s": %v`, key, err)
+			continue
+		}
+		if cfg.Username != "" || cfg.Password != "" || cfg.IdentityToken != "" || cfg.RegistryToken != "" {
+			c.Titlef(`Loaded authentication data for "%s"`, key)
+			return &cfg
+		}
+	}
+	return nil
+}
+
+func registryHostname(registryUrl string) string {
+	u, err := url.Parse(registryUrl)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(registryUrl, "/")
+	}
+	return u.Host
+}
+
+// getRegistryClient returns cached client for registry or mirror URL
+func (c *DockerConfigurator) getRegistryClient(registryUrl string) (*registry.Registry, error) {
+	u := strings.TrimSuffix(registryUrl, "/")
+	if reg, ok := c.registries[u]; ok {
+		return reg, nil
+	}
+	auth := c.authConfig
+	if registryUrl != c.RegistryUrl {
+		auth = c.registryAuthConfig(registryUrl)
+	}
+	username, password := "", ""
+	if auth != nil {
+		username, password = auth.Username, auth.Password
+	}
+	reg := &registry.Registry{
+		URL: u,
+		Client: &http.Client{
+			Transport: &registry.ErrorTransport{
+				Transport: &registry.BasicTransport{
+					Transport: &registryTokenTransport{Transport: http.DefaultTransport, Auth: auth},
+					URL:       u,
+					Username:  username,
+					Password:  password,
+				},
+			},
+		},
+		Logf: func(format string, args ...interface{}) {
+			c.Tracef(format, args...)
+		},
+	}
+	err := c.Retry.do(context.Background(), &c.Logger, reg.Ping)
+	if err != nil {
+		return nil, fmt.Errorf("registry %s is not available: %v", u, err)
+	}
+	c.registries[u] = reg
+	return reg, nil
+}
+
+// mirrorRepository returns repository name in mirror, Docker Hub official images live in library namespace
+func (c *DockerConfigurator) mirrorRepository(ref string) string {
+	if c.registryHost != "" {
+		ref = strings.TrimPrefix(ref, c.registryHost+"/")
+	}
+	if !strings.Contains(repositoryName(ref), "/") {
+		ref = "library/" + ref
+	}
+	return ref
+}
+
+func (c *DockerConfigurator) mirrorImageRef(mirror string, ref string) string {
+	return registryHostname(mirror) + "/" + c.mirrorRepository(ref)
+}
+
+func registryAuthHeader(auth *configtypes.AuthConfig) (string, error) {
+	if auth == nil {
+		return "", nil
+	}
+	buf, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(buf), nil
+}
+
+func (c *DockerConfigurator) pullOptions(auth *configtypes.AuthConfig) image.PullOptions {
+	pullOptions := image.PullOptions{}
+	registryAuth, err := registryAuthHeader(auth)
+	if err != nil {
+		c.Errorf("Failed to prepare registry authentication config: %v", err)
+		return pullOptions
+	}
+	pullOptions.RegistryAuth = registryAuth
+	return pullOptions
+}
+
+var nextLinkRegexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+type tagsPage struct {
+	Tags []string `json:"tags"`
+}
+
+// registryTags lists all repository tags following Link header pagination, every page is retried separately
+func (c *DockerConfigurator) registryTags(reg *registry.Registry, repository string) ([]string, error) {
+	next, err := url.Parse(fmt.Sprintf("%s/v2/%s/tags/list", reg.URL, repository))
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	seen := make(map[string]struct{})
+	for next != nil {
+		if _, ok := seen[next.String()]; ok {
+			return nil, fmt.Errorf("pagination loop at %s", next)
+		}
+		seen[next.String()] = struct{}{}
+		var page tagsPage
+		var link string
+		err := c.Retry.do(context.Background(), &c.Logger, func() error {
+			c.Tracef("registry.tags url=%s repository=%s", next, repository)
+			resp, err := reg.Client.Get(next.String())
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			page = tagsPage{}
+			err = json.NewDecoder(resp.Body).Decode(&page)
+			if err != nil {
+				return fmt.Errorf("invalid tags list: %v", err)
+			}
+			link = resp.Header.Get("Link")
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page.Tags...)
+		next, err = nextPage(next, link)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// nextPage resolves next page URL from Link header, registries usually return it relative to registry root
+func nextPage(current *url.URL, link string) (*url.URL, error) {
+	match := nextLinkRegexp.FindStringSubmatch(link)
+	if match == nil {
+		return nil, nil
+	}
+	ref, err := url.Parse(match[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid next page link %s: %v", match[1], err)
+	}
+	return current.ResolveReference(ref), nil
+}
@@ -0,0 +1,293 @@
+package selenoid
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	authconfig "github.com/docker/cli/cli/config"
+	assert "github.com/stretchr/testify/require"
+)
+
+const testRegistryToken = "test-token"
+
+// tokenRegistry is a registry accepting only bearer tokens issued by its token server
+func tokenRegistry(t *testing.T, issue func(r *http.Request) bool) *httptest.Server {
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") == "Bearer "+testRegistryToken {
+			return true
+		}
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test-registry",scope="repository:selenoid/chrome:pull"`, srv.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+	mux.HandleFunc("/v2/selenoid/chrome/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			w.Header().Add("Content-Type", "application/json")
+			_, _ = fmt.Fprintln(w, `{"name":"selenoid/chrome", "tags": ["119.0", "latest", "120.0"]}`)
+		}
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "test-registry", r.Form.Get("service"))
+		assert.Equal(t, "repository:selenoid/chrome:pull", r.Form.Get("scope"))
+		if !issue(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "%s"}`, testRegistryToken)
+	})
+	srv = httptest.NewServer(mux)
+	return srv
+}
+
+func withDockerConfig(t *testing.T, cfg string, fn func(t *testing.T, dir string)) {
+	withTmpDir(t, "test-registry-config", func(t *testing.T, dir string) {
+		defer authconfig.SetDir(authconfig.Dir())
+		authconfig.SetDir(dir)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(cfg), 0644))
+		fn(t, dir)
+	})
+}
+
+func TestRegistryTokenAuth(t *testing.T) {
+	srv := tokenRegistry(t, func(r *http.Request) bool {
+		username, password, ok := r.BasicAuth()
+		return r.Method == http.MethodGet && ok && username == "user" && password == "secret"
+	})
+	defer srv.Close()
+
+	cfg := fmt.Sprintf(`{"auths": {"%s": {"auth": "dXNlcjpzZWNyZXQ="}}}`, hostPort(srv.URL))
+	withDockerConfig(t, cfg, func(t *testing.T, _ string) {
+		c, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: srv.URL, Retry: testRetryPolicy})
+		assert.NoError(t, err)
+		defer c.Close()
+		assert.Equal(t, "user", c.authConfig.Username)
+		assert.Equal(t, []string{"120.0", "119.0"}, c.fetchImageTags("selenoid/chrome"))
+	})
+}
+
+func TestRegistryCredentialHelper(t *testing.T) {
+	srv := tokenRegistry(t, func(r *http.Request) bool {
+		return r.Method == http.MethodPost && r.Form.Get("grant_type") == "refresh_token" && r.Form.Get("refresh_token") == "refresh-token"
+	})
+	defer srv.Close()
+
+	cfg := fmt.Sprintf(`{"credHelpers": {"%s": "test"}}`, hostPort(srv.URL))
+	withDockerConfig(t, cfg, func(t *testing.T, dir string) {
+		helper := "#!/bin/sh\nread server\necho \"{\\\"ServerURL\\\": \\\"$server\\\", \\\"Username\\\": \\\"<token>\\\", \\\"Secret\\\": \\\"refresh-token\\\"}\"\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "docker-credential-test"), []byte(helper), 0755))
+		t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		c, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: srv.URL, Retry: testRetryPolicy})
+		assert.NoError(t, err)
+		defer c.Close()
+		assert.Equal(t, "refresh-token", c.authConfig.IdentityToken)
+		assert.Equal(t, []string{"120.0", "119.0"}, c.fetchImageTags("selenoid/chrome"))
+	})
+}
+
+func TestRegistryUnauthorized(t *testing.T) {
+	srv := tokenRegistry(t, func(r *http.Request) bool {
+		return false
+	})
+	defer srv.Close()
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: srv.URL, Retry: testRetryPolicy})
+	assert.NoError(t, err)
+	defer c.Close()
+	_, err = c.getRegistryClient(srv.URL)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to obtain registry token")
+	assert.Empty(t, c.fetchImageTags("selenoid/chrome"))
+}
+
+func TestFetchImageTagsFromMirror(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer broken.Close()
+	var requested []string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/v2/library/firefox/tags/list" {
+			w.Header().Add("Content-Type", "application/json")
+			_, _ = fmt.Fprintln(w, `{"name":"library/firefox", "tags": ["47.0"]}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mirror.Close()
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{
+		RegistryUrl:     mockDockerServer.URL,
+		RegistryMirrors: []string{broken.URL, mirror.URL},
+		Retry:           testRetryPolicy,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, []string{"47.0"}, c.fetchImageTags("firefox"))
+	assert.Equal(t, []string{"/v2/", "/v2/library/firefox/tags/list"}, requested)
+
+	c.RegistryMirrors = []string{broken.URL}
+	assert.Equal(t, []string{"46.0", "45.0", "7.0"}, c.fetchImageTags("selenoid/firefox"))
+}
+
+func TestPullImageFromMirror(t *testing.T) {
+	var requests requestRecorder
+	srv := mockDockerServerWith(func(w http.ResponseWriter, r *http.Request) bool {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/images/create"):
+			requests.record("pull " + r.URL.Query().Get("fromImage") + colon + r.URL.Query().Get("tag"))
+		case strings.HasSuffix(r.URL.Path, "/tag"):
+			requests.record("tag " + strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1.29/images/"), "/tag") + " " + r.URL.Query().Get("repo") + colon + r.URL.Query().Get("tag"))
+		case r.Method == http.MethodDelete:
+			requests.record("remove " + strings.TrimPrefix(r.URL.Path, "/v1.29/images/") + " noprune=" + r.URL.Query().Get("noprune"))
+			_, _ = fmt.Fprint(w, `[{"Untagged": "image"}]`)
+			return true
+		}
+		return false
+	})
+	defer srv.Close()
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(srv.URL))
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{
+		RegistryUrl:     DefaultRegistryUrl,
+		RegistryMirrors: []string{"https://missing.example.com", "https://mirror.example.com"},
+		Retry:           testRetryPolicy,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.NoError(t, c.pullImage(context.Background(), "selenoid/firefox:46.0"))
+	assert.Equal(t, []string{
+		"pull missing.example.com/selenoid/firefox:46.0",
+		"pull mirror.example.com/selenoid/firefox:46.0",
+		"tag mirror.example.com/selenoid/firefox:46.0 selenoid/firefox:46.0",
+		"remove mirror.example.com/selenoid/firefox:46.0 noprune=1",
+	}, requests.recorded())
+
+	requests.reset()
+	c.RegistryMirrors = []string{"https://missing.example.com"}
+	assert.NoError(t, c.pullImage(context.Background(), "selenoid/firefox:46.0"))
+	assert.Equal(t, []string{"pull missing.example.com/selenoid/firefox:46.0", "pull selenoid/firefox:46.0"}, requests.recorded())
+}
+
+func TestRegistryTokenCache(t *testing.T) {
+	var issued int32
+	srv := tokenRegistry(t, func(r *http.Request) bool {
+		atomic.AddInt32(&issued, 1)
+		return true
+	})
+	defer srv.Close()
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: srv.URL, Retry: testRetryPolicy})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, []string{"120.0", "119.0"}, c.fetchImageTags("selenoid/chrome"))
+	assert.Equal(t, []string{"120.0", "119.0"}, c.fetchImageTags("selenoid/chrome"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&issued))
+}
+
+func TestRequestScope(t *testing.T) {
+	for path, scope := range map[string]string{
+		"/v2/":                               "",
+		"/v2/selenoid/chrome/tags/list":      "repository:selenoid/chrome:pull",
+		"/v2/library/ubuntu/manifests/22.04": "repository:library/ubuntu:pull",
+		"/v2/selenoid/chrome/blobs/sha256:0": "repository:selenoid/chrome:pull",
+		"/other/selenoid/chrome/tags/list":   "",
+	} {
+		req, err := http.NewRequest(http.MethodGet, "https://registry.example.com"+path, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, scope, requestScope(req), path)
+	}
+}
+
+func TestMirrorImageRef(t *testing.T) {
+	c := &DockerConfigurator{registryHost: "registry.example.com"}
+	assert.Equal(t, "mirror.example.com/library/ubuntu:22.04", c.mirrorImageRef("https://mirror.example.com", "ubuntu:22.04"))
+	assert.Equal(t, "mirror.example.com:5000/selenoid/chrome:120.0", c.mirrorImageRef("http://mirror.example.com:5000/", "registry.example.com/selenoid/chrome:120.0"))
+	assert.Equal(t, "mirror.example.com/library/chrome@sha256:0", c.mirrorImageRef("mirror.example.com", "chrome@sha256:0"))
+}
+
+func TestBearerChallenge(t *testing.T) {
+	assert.Equal(t, map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io"},
+		bearerChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`))
+	assert.Nil(t, bearerChallenge(`Basic realm="registry"`))
+	assert.Nil(t, bearerChallenge(`Bearer service="registry.docker.io"`))
+}
+
+func TestFetchImageTagsPaginated(t *testing.T) {
+	pages := map[string]string{
+		"":      `{"tags": ["118.0", "latest"]}`,
+		"118.0": `{"tags": ["120.0-beta", "dev"]}`,
+		"dev":   `{"tags": ["119.0", "120.0"]}`,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/v2/selenoid/chrome/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		last := r.URL.Query().Get("last")
+		if last != "dev" {
+			next := []string{"118.0", "dev"}[len(last)/4]
+			w.Header().Set("Link", fmt.Sprintf(`</v2/selenoid/chrome/tags/list?n=2&last=%s>; rel="next"`, next))
+		}
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprintln(w, pages[last])
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: srv.URL, Retry: testRetryPolicy})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, []string{"120.0", "119.0", "118.0"}, c.fetchImageTags("selenoid/chrome"))
+	c.IncludePrereleases = true
+	assert.Equal(t, []string{"120.0", "120.0-beta", "119.0", "118.0"}, c.fetchImageTags("selenoid/chrome"))
+}
+
+func TestLatestImageVersionSkipsPrereleases(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/v2/aerokube/selenoid/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprintln(w, `{"tags": ["1.11.0", "1.12.0-rc1", "latest"]}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: srv.URL, Retry: testRetryPolicy, IncludePrereleases: true})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, "1.11.0", *c.getLatestImageVersion("aerokube/selenoid"))
+}
+
+func TestNextPage(t *testing.T) {
+	current, _ := url.Parse("https://registry.example.com/v2/selenoid/chrome/tags/list")
+	next, err := nextPage(current, `</v2/selenoid/chrome/tags/list?last=1&n=100>; rel="next"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://registry.example.com/v2/selenoid/chrome/tags/list?last=1&n=100", next.String())
+	next, err = nextPage(current, `<https://other.example.com/v2/tags?last=2>; rel=next`)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://other.example.com/v2/tags?last=2", next.String())
+	next, err = nextPage(current, "")
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+}
@@ -0,0 +1,240 @@
+package selenoid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-github/github"
+	"github.com/heroku/docker-registry-client/registry"
+)
+
+const (
+	DefaultRetryAttempts = 3
+	DefaultRetryBackoff  = time.Second
+	DefaultRetryJitter   = 0.2
+	maxRetryBackoff      = time.Minute
+)
+
+// RetryPolicy describes how network operations are retried after transient failures
+type RetryPolicy struct {
+	// Attempts is the total number of attempts, values less than 2 disable retries
+	Attempts int
+	// Backoff is a delay before the second attempt, doubled after every next attempt
+	Backoff time.Duration
+	// Jitter is a fraction of delay randomly added to or subtracted from it
+	Jitter float64
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts: DefaultRetryAttempts,
+		Backoff:  DefaultRetryBackoff,
+		Jitter:   DefaultRetryJitter,
+	}
+}
+
+// httpStatusError is returned for unsuccessful HTTP responses received by our own code
+type httpStatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected response code: %d", e.StatusCode)
+}
+
+func newHttpStatusError(resp *http.Response) error {
+	return &httpStatusError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp)}
+}
+
+// retryingTransport repeats GET and HEAD requests after transient failures according to retry policy
+type retryingTransport struct {
+	Transport http.RoundTripper
+	Policy    RetryPolicy
+	Logger    *Logger
+}
+
+// httpClient returns HTTP client retrying idempotent requests, unsuccessful responses are returned as errors
+func (p RetryPolicy) httpClient(logger *Logger) *http.Client {
+	return &http.Client{Transport: &retryingTransport{Transport: http.DefaultTransport, Policy: p, Logger: logger}}
+}
+
+func (t *retryingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.Transport.RoundTrip(req)
+	}
+	var resp *http.Response
+	err := t.Policy.do(req.Context(), t.Logger, func() error {
+		var err error
+		resp, err = t.Transport.RoundTrip(req)
+		if err != nil {
+			return err
+		}
+		if isRetryableStatus(resp.StatusCode) {
+			_ = resp.Body.Close()
+			return newHttpStatusError(resp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// do calls op until it succeeds, returns a non-retryable error or attempts are exhausted
+func (p RetryPolicy) do(ctx context.Context, logger *Logger, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if rateErr := rateLimitExceeded(err); rateErr != nil {
+			return rateErr
+		}
+		if attempt >= p.Attempts {
+			return err
+		}
+		retryable, retryAfter := isRetryable(err)
+		if !retryable {
+			return err
+		}
+		delay := p.retryDelay(attempt, retryAfter)
+		if logger != nil {
+			logger.Pointf("Attempt %d of %d failed: %v. Retrying in %v...", attempt, p.Attempts, err, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// retryDelay respects delay requested by server unless it is longer than maximum backoff
+func (p RetryPolicy) retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > maxRetryBackoff {
+		retryAfter = maxRetryBackoff
+	}
+	delay := p.delay(attempt)
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration(p.Jitter * float64(delay) * (2*rand.Float64() - 1))
+	}
+	return delay
+}
+
+// rateLimitExceeded reports GitHub primary rate limit that is lifted too late to wait for it
+func rateLimitExceeded(err error) error {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) && time.Until(rateErr.Rate.Reset.Time) > maxRetryBackoff {
+		return fmt.Errorf("GitHub rate limit exceeded, resets at %s: %v", rateErr.Rate.Reset.Time.Local().Format(time.RFC1123), err)
+	}
+	return nil
+}
+
+func isRetryable(err error) (bool, time.Duration) {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return isRetryableStatus(statusErr.StatusCode), statusErr.RetryAfter
+	}
+	var registryErr *registry.HTTPStatusError
+	if errors.As(err, &registryErr) {
+		return isRetryableStatus(registryErr.Response.StatusCode), parseRetryAfter(registryErr.Response)
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return true, *abuseErr.RetryAfter
+		}
+		return true, 0
+	}
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		// Primary rate limit is lifted at reset time only, retrying earlier is useless
+		return true, time.Until(rateErr.Rate.Reset.Time)
+	}
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) {
+		return isRetryableStatus(githubErr.Response.StatusCode), parseRetryAfter(githubErr.Response)
+	}
+	if errdefs.IsUnavailable(err) || errdefs.IsDeadline(err) {
+		return true, 0
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true, 0
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true, 0
+	}
+	return isRetryableMessage(err.Error()), 0
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// Errors reported by Docker daemon in pull progress are only available as text
+var retryableMessages = []string{
+	"toomanyrequests",
+	"connection reset",
+	"unexpected EOF",
+	"i/o timeout",
+	"TLS handshake timeout",
+	"500 Internal Server Error",
+	"502 Bad Gateway",
+	"503 Service Unavailable",
+	"504 Gateway Timeout",
+}
+
+func isRetryableMessage(msg string) bool {
+	for _, m := range retryableMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRetryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
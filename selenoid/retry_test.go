@@ -0,0 +1,195 @@
+package selenoid
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+	assert "github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+
+func failingServer(failures int32, fail func(w http.ResponseWriter), ok func(w http.ResponseWriter)) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			fail(w)
+			return
+		}
+		ok(w)
+	}))
+	return srv, &requests
+}
+
+func respondWith(code int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(code)
+	}
+}
+
+func respondWithData(w http.ResponseWriter) {
+	_, _ = w.Write([]byte("data"))
+}
+
+func TestRetryDownload(t *testing.T) {
+	srv, requests := failingServer(2, respondWith(http.StatusServiceUnavailable), respondWithData)
+	defer srv.Close()
+
+	data, err := downloadFile(srv.URL, testRetryPolicy, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assert.Equal(t, int32(3), *requests)
+}
+
+func TestRetryAttemptsExhausted(t *testing.T) {
+	srv, requests := failingServer(5, respondWith(http.StatusTooManyRequests), respondWithData)
+	defer srv.Close()
+
+	_, err := downloadFile(srv.URL, testRetryPolicy, nil)
+	assert.EqualError(t, err, "unexpected response code: 429")
+	assert.Equal(t, int32(3), *requests)
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	srv, requests := failingServer(1, respondWith(http.StatusNotFound), respondWithData)
+	defer srv.Close()
+
+	_, err := downloadFile(srv.URL, testRetryPolicy, nil)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), *requests)
+}
+
+func TestNoRetryByDefault(t *testing.T) {
+	srv, requests := failingServer(1, respondWith(http.StatusBadGateway), respondWithData)
+	defer srv.Close()
+
+	_, err := downloadFile(srv.URL, RetryPolicy{}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), *requests)
+}
+
+func TestRetryTruncatedDownload(t *testing.T) {
+	srv, requests := failingServer(1, func(w http.ResponseWriter) {
+		w.Header().Set("Content-Length", "100")
+		_, _ = w.Write([]byte("trunc"))
+	}, respondWithData)
+	defer srv.Close()
+
+	data, err := downloadFile(srv.URL, testRetryPolicy, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assert.Equal(t, int32(2), *requests)
+}
+
+func TestRetryCatalog(t *testing.T) {
+	srv, requests := failingServer(1, respondWith(http.StatusInternalServerError), func(w http.ResponseWriter) {
+		_, _ = fmt.Fprintln(w, `{"chrome": {"image": "example.com/chrome"}}`)
+	})
+	defer srv.Close()
+
+	catalog, err := LoadCatalog(srv.URL+"/catalog.json", testRetryPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"chrome"}, catalog.Names())
+	assert.Equal(t, int32(2), *requests)
+}
+
+func TestRetryFetchImageTags(t *testing.T) {
+	var requests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/v2/selenoid/chrome/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprintln(w, `{"name":"chrome", "tags": ["120.0", "119.0"]}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: srv.URL, Retry: testRetryPolicy})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, []string{"120.0", "119.0"}, c.fetchImageTags("selenoid/chrome"))
+	assert.Equal(t, int32(3), requests)
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{Attempts: 10, Backoff: time.Second}
+	assert.Equal(t, time.Second, p.delay(1))
+	assert.Equal(t, 2*time.Second, p.delay(2))
+	assert.Equal(t, 8*time.Second, p.delay(4))
+	assert.Equal(t, maxRetryBackoff, p.delay(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		delay := p.delay(2)
+		assert.True(t, delay >= time.Second && delay <= 3*time.Second, delay)
+	}
+}
+
+func TestRetryDelayCapped(t *testing.T) {
+	p := RetryPolicy{Attempts: 3, Backoff: time.Second}
+	assert.Equal(t, 5*time.Second, p.retryDelay(1, 5*time.Second))
+	assert.Equal(t, 2*time.Second, p.retryDelay(2, time.Millisecond))
+	assert.Equal(t, maxRetryBackoff, p.retryDelay(1, time.Hour))
+}
+
+func TestRateLimitResetTooLate(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	calls := 0
+	err := testRetryPolicy.do(context.Background(), nil, func() error {
+		calls++
+		return &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}, Message: "API rate limit exceeded"}
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GitHub rate limit exceeded, resets at "+reset.Local().Format(time.RFC1123))
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = testRetryPolicy.do(context.Background(), nil, func() error {
+		calls++
+		if calls == 1 {
+			return &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: time.Now()}}}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	assert.Equal(t, time.Duration(0), parseRetryAfter(resp))
+	resp.Header.Set("Retry-After", "5")
+	assert.Equal(t, 5*time.Second, parseRetryAfter(resp))
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, parseRetryAfter(resp) > 50*time.Second)
+	resp.Header.Set("Retry-After", "garbage")
+	assert.Equal(t, time.Duration(0), parseRetryAfter(resp))
+}
+
+func TestIsRetryable(t *testing.T) {
+	retryable, retryAfter := isRetryable(&httpStatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second})
+	assert.True(t, retryable)
+	assert.Equal(t, time.Second, retryAfter)
+	retryable, _ = isRetryable(&httpStatusError{StatusCode: http.StatusForbidden})
+	assert.False(t, retryable)
+	retryable, _ = isRetryable(fmt.Errorf("toomanyrequests: You have reached your pull rate limit"))
+	assert.True(t, retryable)
+	retryable, _ = isRetryable(fmt.Errorf("manifest unknown"))
+	assert.False(t, retryable)
+	reset := time.Now().Add(time.Minute)
+	retryable, retryAfter = isRetryable(&github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}})
+	assert.True(t, retryable)
+	assert.InDelta(t, time.Minute, retryAfter, float64(time.Second))
+}
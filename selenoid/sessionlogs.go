@@ -0,0 +1,73 @@
+package selenoid
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const sessionLogExtension = ".log"
+
+type SessionLog struct {
+	Session  string    `json:"session" yaml:"session"`
+	Size     int64     `json:"size" yaml:"size"`
+	Modified time.Time `json:"modified" yaml:"modified"`
+}
+
+// ListSessionLogs returns logs saved by Selenoid for every browser session, newest first
+func (l *Lifecycle) ListSessionLogs() ([]SessionLog, error) {
+	entries, err := os.ReadDir(l.getSessionLogsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs directory: %v", err)
+	}
+	var ret []SessionLog
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isSessionLog(name) {
+			continue
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		ret = append(ret, SessionLog{
+			Session:  strings.TrimSuffix(name, sessionLogExtension),
+			Size:     fi.Size(),
+			Modified: fi.ModTime(),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Modified.After(ret[j].Modified)
+	})
+	return ret, nil
+}
+
+func (l *Lifecycle) SessionLog(w io.Writer, session string) error {
+	name := session + sessionLogExtension
+	if filepath.Base(name) != name || !isSessionLog(name) {
+		return fmt.Errorf("invalid session ID: %s", session)
+	}
+	f, err := os.Open(filepath.Join(l.getSessionLogsDir(), name))
+	if err != nil {
+		return fmt.Errorf("failed to open session log: %v", err)
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func (l *Lifecycle) getSessionLogsDir() string {
+	return filepath.Join(l.Config.ConfigDir, logsDirName)
+}
+
+// isSessionLog excludes Selenoid and Selenoid UI logs saved by cm itself
+func isSessionLog(name string) bool {
+	return strings.HasSuffix(name, sessionLogExtension) && name != selenoidLogFileName && name != selenoidUILogFileName
+}
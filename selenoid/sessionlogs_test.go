@@ -0,0 +1,37 @@
+package selenoid
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSessionLogs(t *testing.T) {
+	withTmpDir(t, "session-logs", func(t *testing.T, dir string) {
+		lc, err := NewLifecycle(&LifecycleConfig{Quiet: true, UseDrivers: true, ConfigDir: dir})
+		assert.NoError(t, err)
+		logs, err := lc.ListSessionLogs()
+		assert.NoError(t, err)
+		assert.Empty(t, logs)
+
+		logsDir := filepath.Join(dir, logsDirName)
+		assert.NoError(t, os.MkdirAll(logsDir, os.ModePerm))
+		for _, name := range []string{"abcdef.log", selenoidLogFileName, selenoidUILogFileName, selenoidLogFileName + ".1", "video.mp4"} {
+			assert.NoError(t, os.WriteFile(filepath.Join(logsDir, name), []byte("Session logs..."), 0644))
+		}
+		logs, err = lc.ListSessionLogs()
+		assert.NoError(t, err)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, "abcdef", logs[0].Session)
+
+		var buf bytes.Buffer
+		assert.NoError(t, lc.SessionLog(&buf, "abcdef"))
+		assert.Equal(t, "Session logs...", buf.String())
+		assert.Error(t, lc.SessionLog(&buf, "missing"))
+		assert.Error(t, lc.SessionLog(&buf, "../abcdef"))
+		assert.Error(t, lc.SessionLog(&buf, "selenoid"))
+	})
+}
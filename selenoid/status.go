@@ -0,0 +1,136 @@
+package selenoid
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+type State string
+
+const (
+	StateRunning       State = "running"
+	StateStopped       State = "stopped"
+	StateNotConfigured State = "not configured"
+
+	ModeDocker  = "docker"
+	ModeDrivers = "drivers"
+)
+
+type Status struct {
+	Service     string                     `json:"service" yaml:"service"`
+	Instance    string                     `json:"instance,omitempty" yaml:"instance,omitempty"`
+	Mode        string                     `json:"mode" yaml:"mode"`
+	State       State                      `json:"state" yaml:"state"`
+	Image       *ImageInfo                 `json:"image,omitempty" yaml:"image,omitempty"`
+	Binary      string                     `json:"binary,omitempty" yaml:"binary,omitempty"`
+	ConfigDir   string                     `json:"configDir,omitempty" yaml:"configDir,omitempty"`
+	ConfigPath  string                     `json:"configPath,omitempty" yaml:"configPath,omitempty"`
+	ContainerID string                     `json:"containerId,omitempty" yaml:"containerId,omitempty"`
+	Pid         int                        `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Port        int                        `json:"port,omitempty" yaml:"port,omitempty"`
+	StartedAt   *time.Time                 `json:"startedAt,omitempty" yaml:"startedAt,omitempty"`
+	Uptime      string                     `json:"uptime,omitempty" yaml:"uptime,omitempty"`
+	Browsers    map[string]BrowserVersions `json:"browsers,omitempty" yaml:"browsers,omitempty"`
+}
+
+type ImageInfo struct {
+	Tag string `json:"tag" yaml:"tag"`
+	ID  string `json:"id" yaml:"id"`
+}
+
+type BrowserVersions struct {
+	Default  string   `json:"default" yaml:"default"`
+	Versions []string `json:"versions" yaml:"versions"`
+}
+
+func (s *Status) IsRunning() bool {
+	return s.State == StateRunning
+}
+
+// setState determines service state from the facts collected by configurator
+func (s *Status) setState(running bool, configured bool) {
+	switch {
+	case running:
+		s.State = StateRunning
+	case configured:
+		s.State = StateStopped
+	default:
+		s.State = StateNotConfigured
+	}
+}
+
+func (s *Status) setStartedAt(startedAt time.Time) {
+	if startedAt.IsZero() {
+		return
+	}
+	s.StartedAt = &startedAt
+	s.Uptime = time.Since(startedAt).Round(time.Second).String()
+}
+
+func readBrowsersStatus(configPath string) map[string]BrowserVersions {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil
+	}
+	var cfg SelenoidConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil
+	}
+	ret := make(map[string]BrowserVersions)
+	for name, versions := range cfg {
+		bv := BrowserVersions{Default: versions.Default, Versions: []string{}}
+		for v := range versions.Versions {
+			bv.Versions = append(bv.Versions, v)
+		}
+		sort.Strings(bv.Versions)
+		ret[name] = bv
+	}
+	return ret
+}
+
+func (l *Lifecycle) PrintStatus(s *Status) {
+	if s.Image != nil {
+		l.Pointf("Using %s image: %s (%s)", s.Service, s.Image.Tag, s.Image.ID)
+	} else if s.Binary != "" {
+		l.Pointf("%s binary is %s", s.Service, s.Binary)
+	} else {
+		l.Pointf("%s is not downloaded", s.Service)
+	}
+	if s.ConfigDir != "" {
+		l.Pointf("%s configuration directory is %s", s.Service, s.ConfigDir)
+		if s.ConfigPath != "" {
+			l.Pointf("%s configuration file is %s", s.Service, s.ConfigPath)
+		} else {
+			l.Pointf("%s is not configured", s.Service)
+		}
+	}
+	var names []string
+	for name := range s.Browsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		bv := s.Browsers[name]
+		l.Pointf("Browser %s: versions %v, default %s", color.GreenString(name), bv.Versions, bv.Default)
+	}
+	switch {
+	case !s.IsRunning():
+		l.Pointf("%s is not running", s.Service)
+	case s.ContainerID != "":
+		l.Pointf("%s container is running: %s (%s)", s.Service, s.ContainerID, s.portAndUptime())
+	default:
+		l.Pointf("%s is running as process %d (%s)", s.Service, s.Pid, s.portAndUptime())
+	}
+}
+
+func (s *Status) portAndUptime() string {
+	ret := "port " + color.BlueString("%d", s.Port)
+	if s.Uptime != "" {
+		ret += ", up " + s.Uptime
+	}
+	return ret
+}
@@ -0,0 +1,74 @@
+package selenoid
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	supervisorMinBackoff = time.Second
+	supervisorMaxBackoff = time.Minute
+
+	// Process running longer than this is considered healthy and backoff is reset
+	supervisorResetAfter = time.Minute
+)
+
+// Supervise runs Selenoid in foreground and restarts it with exponential backoff until stop is closed
+func (d *DriversConfigurator) Supervise(stop <-chan struct{}) error {
+	logs, err := newRotatingWriter(d.getServiceLogPath(selenoidLogFileName), serviceLogMaxSize, serviceLogBackups)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+	var output io.Writer = logs
+	if !d.Quiet {
+		output = io.MultiWriter(os.Stdout, logs)
+	}
+	stateFile := d.getStateFilePath(selenoidStateFileName)
+	defer removeProcessState(stateFile)
+
+	backoff := supervisorMinBackoff
+	for {
+		startedAt := time.Now()
+		p, err := startSupervisedProcess(d.getSelenoidBinaryPath(), d.selenoidArgs(), d.Env, output)
+		if err != nil {
+			return fmt.Errorf("failed to start Selenoid: %v", err)
+		}
+		err = writeProcessState(stateFile, p, true)
+		if err != nil {
+			return fmt.Errorf("failed to save process state: %v", err)
+		}
+		d.Titlef("Started Selenoid as process %d", p.cmd.Process.Pid)
+		select {
+		case <-p.done:
+		case <-stop:
+			d.Titlef("Stopping Selenoid...")
+			err = killFunc(p.cmd.Process, d.Graceful, d.GracefulTimeout)
+			<-p.done
+			return err
+		}
+		if time.Since(startedAt) > supervisorResetAfter {
+			backoff = supervisorMinBackoff
+		}
+		d.Errorf("Selenoid exited: %v, restarting in %v", p.err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-stop:
+			return nil
+		}
+		backoff *= 2
+		if backoff > supervisorMaxBackoff {
+			backoff = supervisorMaxBackoff
+		}
+	}
+}
+
+func startSupervisedProcess(command string, args []string, env []string, output io.Writer) (*startedProcess, error) {
+	cmd := execCommand(command, args...)
+	cmd.Env = env
+	cmd.Stdout = output
+	cmd.Stderr = output
+	return startCommand(cmd)
+}
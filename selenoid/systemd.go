@@ -0,0 +1,182 @@
+package selenoid
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	DefaultSystemdUnitDir = "/etc/systemd/system"
+
+	dockerBinary = "/usr/bin/docker"
+)
+
+var systemdUserUnitDirElem = []string{".config", "systemd", "user"}
+
+// SystemdUnit is a systemd service starting Selenoid or Selenoid UI with the same settings as start command
+type SystemdUnit struct {
+	Name        string
+	Description string
+	// User unit is run by user systemd instance, e.g. with rootless engine listening on user socket
+	User         bool
+	After        []string
+	Requires     []string
+	Environment  []string
+	ExecStartPre []string
+	ExecStart    string
+	ExecStop     []string
+}
+
+var systemdUnitTemplate = template.Must(template.New("unit").Parse(`[Unit]
+Description={{.Description}}
+{{- if .User}}
+{{- if .After}}
+After={{range $i, $unit := .After}}{{if $i}} {{end}}{{$unit}}{{end}}
+{{- end}}
+{{- else}}
+After={{range .After}}{{.}} {{end}}network-online.target
+Wants=network-online.target
+{{- end}}
+{{- range .Requires}}
+Requires={{.}}
+{{- end}}
+
+[Service]
+{{- range .Environment}}
+Environment={{.}}
+{{- end}}
+{{- range .ExecStartPre}}
+ExecStartPre={{.}}
+{{- end}}
+ExecStart={{.ExecStart}}
+{{- range .ExecStop}}
+ExecStop={{.}}
+{{- end}}
+Restart=always
+RestartSec=5
+
+[Install]
+WantedBy={{if .User}}default.target{{else}}multi-user.target{{end}}
+`))
+
+func (u *SystemdUnit) Render(w io.Writer) error {
+	return systemdUnitTemplate.Execute(w, u)
+}
+
+// DefaultDir returns directory where systemd looks for system or user units
+func (u *SystemdUnit) DefaultDir() string {
+	if u.User {
+		return joinPaths(getHomeDir(), systemdUserUnitDirElem)
+	}
+	return DefaultSystemdUnitDir
+}
+
+// Install saves unit file to directory and returns its path
+func (u *SystemdUnit) Install(dir string) (string, error) {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("failed to create unit directory: %v", err)
+	}
+	path := filepath.Join(dir, u.Name)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create unit file: %v", err)
+	}
+	defer f.Close()
+	err = u.Render(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to render unit file: %v", err)
+	}
+	return path, nil
+}
+
+func systemdUnitName(i *InstanceAware, service string) string {
+	return i.instanceName(service) + ".service"
+}
+
+// systemdCommandLine quotes arguments and escapes characters having special meaning in unit files
+func systemdCommandLine(args []string) string {
+	var ret []string
+	for _, arg := range args {
+		arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\;") {
+			arg = strconv.Quote(arg)
+		}
+		ret = append(ret, arg)
+	}
+	return strings.Join(ret, " ")
+}
+
+func systemdEnvironment(env []string) []string {
+	var ret []string
+	for _, e := range env {
+		ret = append(ret, systemdCommandLine([]string{e}))
+	}
+	return ret
+}
+
+func (d *DriversConfigurator) SystemdUnit() (*SystemdUnit, error) {
+	return &SystemdUnit{
+		Name:        systemdUnitName(&d.InstanceAware, selenoidRepo),
+		Description: "Selenoid",
+		Environment: systemdEnvironment(d.Env),
+		ExecStart:   systemdCommandLine(append([]string{d.getSelenoidBinaryPath()}, d.selenoidArgs()...)),
+	}, nil
+}
+
+func (d *DriversConfigurator) UISystemdUnit() (*SystemdUnit, error) {
+	return &SystemdUnit{
+		Name:        systemdUnitName(&d.InstanceAware, selenoidUIRepo),
+		Description: "Selenoid UI",
+		Environment: systemdEnvironment(d.Env),
+		ExecStart:   systemdCommandLine(append([]string{d.getSelenoidUIBinaryPath()}, d.selenoidUIArgs()...)),
+	}, nil
+}
+
+// SystemdUnit refers to image by tag when it is not pulled yet, engine pulls it when unit starts
+func (c *DockerConfigurator) SystemdUnit() (*SystemdUnit, error) {
+	img := c.exportImage(selenoidImage, c.Version)
+	return c.dockerSystemdUnit(c.selenoidContainerConfig(img), "Selenoid", nil), nil
+}
+
+func (c *DockerConfigurator) UISystemdUnit() (*SystemdUnit, error) {
+	img := c.exportImage(selenoidUIImage, c.Version)
+	cfg := c.selenoidUIContainerConfig(img, c.selenoidUri())
+	return c.dockerSystemdUnit(cfg, "Selenoid UI", []string{systemdUnitName(&c.InstanceAware, selenoidRepo)}), nil
+}
+
+// selenoidUri points Selenoid UI to Selenoid container in the same network
+func (c *DockerConfigurator) selenoidUri() string {
+	return fmt.Sprintf("--selenoid-uri=http://%s:%d", c.instanceName(selenoidContainerName), DefaultPort)
+}
+
+// dockerSystemdUnit runs container in foreground so that systemd restarts it instead of Docker
+func (c *DockerConfigurator) dockerSystemdUnit(cfg *containerConfig, description string, requires []string) *SystemdUnit {
+	return &SystemdUnit{
+		Name:        cfg.Name + ".service",
+		Description: description,
+		User:        c.engine.rootless(),
+		After:       append([]string{c.engine.systemdService()}, requires...),
+		Requires:    append([]string{c.engine.systemdService()}, requires...),
+		ExecStartPre: []string{
+			systemdCommandLine([]string{"-" + c.engine.binary(), "rm", "-f", cfg.Name}),
+			systemdCommandLine([]string{"-" + c.engine.binary(), "network", "create", cfg.Network}),
+		},
+		ExecStart: systemdCommandLine(append([]string{c.engine.binary()}, c.exportedContainer(cfg).dockerRunArgs("--rm")...)),
+		ExecStop:  []string{systemdCommandLine([]string{c.engine.binary(), "stop", cfg.Name})},
+	}
+}
+
+// exportedEnv does not include current environment variables unlike startContainer
+func (c *DockerConfigurator) exportedEnv(cfg *containerConfig) []string {
+	env := append([]string{}, cfg.OverrideEnv...)
+	if !contains(env, dockerApiVersion) {
+		env = append(env, fmt.Sprintf("%s=%s", dockerApiVersion, c.docker.ClientVersion()))
+	}
+	return env
+}
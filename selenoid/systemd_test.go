@@ -0,0 +1,122 @@
+package selenoid
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+const goldenConfigDir = "/home/user/.aerokube/selenoid"
+
+func TestDriversSystemdUnits(t *testing.T) {
+	d := NewDriversConfigurator(&LifecycleConfig{
+		ConfigDir: goldenConfigDir,
+		Version:   Latest,
+		Port:      DefaultPort,
+		Env:       []string{"KEY=value", "OTHER=100%"},
+	})
+	unit, err := d.SystemdUnit()
+	assert.NoError(t, err)
+	assert.Equal(t, "selenoid.service", unit.Name)
+	assertGolden(t, unit, "selenoid-drivers.service")
+
+	d.Instance = "team"
+	d.Env = nil
+	d.Args = "-limit 5"
+	d.Port = UIDefaultPort
+	unit, err = d.UISystemdUnit()
+	assert.NoError(t, err)
+	assert.Equal(t, "selenoid-ui-team.service", unit.Name)
+	assertGolden(t, unit, "selenoid-ui-drivers.service")
+}
+
+func TestDockerSystemdUnits(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(mockDockerServer.URL))
+	c, err := NewDockerConfigurator(&LifecycleConfig{
+		ConfigDir:   goldenConfigDir,
+		RegistryUrl: mockDockerServer.URL,
+		Version:     Latest,
+		Port:        DefaultPort,
+		UserNS:      "host",
+	})
+	assert.NoError(t, err)
+	unit, err := c.SystemdUnit()
+	assert.NoError(t, err)
+	assert.Equal(t, "selenoid.service", unit.Name)
+	assertGolden(t, unit, "selenoid-docker.service")
+
+	defer resetImageName()
+	setImageName(selenoidUIImage)
+	c.Port = UIDefaultPort
+	c.UserNS = ""
+	unit, err = c.UISystemdUnit()
+	assert.NoError(t, err)
+	assert.Equal(t, "selenoid-ui.service", unit.Name)
+	assertGolden(t, unit, "selenoid-ui-docker.service")
+	assert.Equal(t, DefaultSystemdUnitDir, unit.DefaultDir())
+}
+
+func TestRootlessPodmanSystemdUnit(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(mockDockerServer.URL))
+	c, err := NewDockerConfigurator(&LifecycleConfig{
+		ConfigDir:   goldenConfigDir,
+		RegistryUrl: mockDockerServer.URL,
+		Version:     Latest,
+		Port:        DefaultPort,
+	})
+	assert.NoError(t, err)
+	c.engine = newEngine(EnginePodman, "unix:///run/user/1000/podman/podman.sock")
+	unit, err := c.SystemdUnit()
+	assert.NoError(t, err)
+	assert.True(t, unit.User)
+	assert.Equal(t, filepath.Join(getHomeDir(), ".config", "systemd", "user"), unit.DefaultDir())
+	assertGolden(t, unit, "selenoid-podman-user.service")
+}
+
+func TestInstallSystemdUnit(t *testing.T) {
+	withTmpDir(t, "systemd", func(t *testing.T, dir string) {
+		unit := &SystemdUnit{Name: "selenoid.service", Description: "Selenoid", ExecStart: "/usr/bin/selenoid"}
+		path, err := unit.Install(filepath.Join(dir, "system"))
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "system", "selenoid.service"), path)
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "ExecStart=/usr/bin/selenoid\n")
+	})
+}
+
+func TestSystemdCommandLine(t *testing.T) {
+	assert.Equal(t, `/bin/selenoid -listen :4444 "" "a b" 100%% $$HOME`, systemdCommandLine([]string{"/bin/selenoid", "-listen", ":4444", "", "a b", "100%", "$HOME"}))
+}
+
+func assertGolden(t *testing.T, unit *SystemdUnit, name string) {
+	var buf bytes.Buffer
+	assert.NoError(t, unit.Render(&buf))
+	assertGoldenOutput(t, buf.String(), name)
+}
+
+// assertGoldenOutput compares output with testdata file replacing host specific values
+func assertGoldenOutput(t *testing.T, output string, name string) {
+	if isWindows() {
+		t.Skip("golden files use Unix paths")
+	}
+	actual := strings.NewReplacer(
+		getSelenoidReleaseFileName(), "selenoid_linux_amd64",
+		getSelenoidUIReleaseFileName(), "selenoid-ui_linux_amd64",
+		hostPort(mockDockerServer.URL), "registry.example.com",
+	).Replace(output)
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		assert.NoError(t, os.WriteFile(path, []byte(actual), 0644))
+	}
+	expected, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), actual)
+}
@@ -0,0 +1,91 @@
+package selenoid
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	. "github.com/fvbommel/sortorder"
+)
+
+// architectures are suffixes of platform specific image tags like 120.0-arm64
+var architectures = []string{"amd64", "arm64", "aarch64", "armv7", "armv6", "arm", "ppc64le", "s390x", "386"}
+
+type tagVersion struct {
+	tag     string
+	version *semver.Version
+}
+
+// sortedTags returns release tags from newest to oldest. Prereleases are added when requested.
+// Tags not following semantic versioning (dev, vnc_*, latest) and architecture specific tags are skipped.
+// When no tag is a version at all, e.g. for custom images, all tags are returned in natural order.
+func sortedTags(tags []string, includePrereleases bool) []string {
+	tags = filterOutLatest(tags)
+	var versions []tagVersion
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil || isArchitectureTag(version) {
+			continue
+		}
+		if version.Prerelease() != "" && !includePrereleases {
+			continue
+		}
+		versions = append(versions, tagVersion{tag, version})
+	}
+	if len(versions) == 0 && !hasVersionTags(tags) {
+		sort.Sort(sort.Reverse(Natural(tags)))
+		return tags
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		if cmp := versions[i].version.Compare(versions[j].version); cmp != 0 {
+			return cmp > 0
+		}
+		return NaturalLess(versions[j].tag, versions[i].tag)
+	})
+	ret := make([]string, 0, len(versions))
+	for _, v := range versions {
+		ret = append(ret, v.tag)
+	}
+	return ret
+}
+
+func hasVersionTags(tags []string) bool {
+	for _, tag := range tags {
+		if _, err := semver.NewVersion(tag); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isArchitectureTag(version *semver.Version) bool {
+	for _, part := range strings.Split(version.Prerelease(), ".") {
+		for _, arch := range strings.Split(part, "-") {
+			for _, a := range architectures {
+				if arch == a {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isStableTag(tag string) bool {
+	version, err := semver.NewVersion(tag)
+	return err != nil || version.Prerelease() == ""
+}
+
+// lastVersions returns tags up to the n-th stable release, newer prereleases are kept
+func lastVersions(tags []string, n int) []string {
+	stable := 0
+	for i, tag := range tags {
+		if isStableTag(tag) {
+			stable++
+		}
+		if stable == n {
+			return tags[:i+1]
+		}
+	}
+	return tags
+}
@@ -0,0 +1,57 @@
+package selenoid
+
+import (
+	"regexp"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSortedTags(t *testing.T) {
+	tags := []string{"9.0", "latest", "120.0-beta", "dev", "vnc_firefox_46.0", "120.0", "119.0-arm64", "119.0", "100.0", "latest-release", "121.0-beta.2"}
+	assert.Equal(t, []string{"120.0", "119.0", "100.0", "9.0"}, sortedTags(tags, false))
+	assert.Equal(t, []string{"121.0-beta.2", "120.0", "120.0-beta", "119.0", "100.0", "9.0"}, sortedTags(tags, true))
+	assert.Equal(t, []string{"1.10.0", "1.9.2", "1.9.1"}, sortedTags([]string{"1.9.1", "1.10.0", "1.9.2"}, false))
+}
+
+func TestSortedTagsWithoutVersions(t *testing.T) {
+	assert.Equal(t, []string{"chrome_10", "chrome_9"}, sortedTags([]string{"chrome_9", "latest", "chrome_10"}, false))
+	assert.Empty(t, sortedTags([]string{"120.0-beta", "dev"}, false))
+}
+
+func TestLastVersions(t *testing.T) {
+	tags := []string{"121.0-beta", "120.0", "120.0-beta", "119.0", "118.0"}
+	assert.Equal(t, []string{"121.0-beta", "120.0", "120.0-beta", "119.0"}, lastVersions(tags, 2))
+	assert.Equal(t, tags, lastVersions(tags, 5))
+	assert.Equal(t, []string{"chrome_10"}, lastVersions([]string{"chrome_10", "chrome_9"}, 1))
+}
+
+func TestFilterTags(t *testing.T) {
+	c := &DockerConfigurator{LastVersions: 2, TagFilter: regexp.MustCompile(`^1[12]`)}
+	assert.Equal(t, []string{"120.0", "119.0"}, c.filterTags([]string{"120.0", "119.0", "118.0", "100.0"}, nil))
+	c.TagFilter = regexp.MustCompile(`^100`)
+	assert.Equal(t, []string{"100.0"}, c.filterTags([]string{"120.0", "119.0", "118.0", "100.0"}, nil))
+}
+
+func TestTagFilterAppliedBeforeSorting(t *testing.T) {
+	tags := []string{"vnc_chrome_120.0", "119.0", "dev", "120.0-arm64", "120.0", "vnc_chrome_119.0", "latest"}
+	c := &DockerConfigurator{TagFilter: regexp.MustCompile(`^vnc_`)}
+	assert.Equal(t, []string{"vnc_chrome_120.0", "vnc_chrome_119.0"}, c.matchingTags(tags))
+	c.TagFilter = regexp.MustCompile(`^(1|vnc_)`)
+	assert.Equal(t, []string{"120.0", "119.0", "vnc_chrome_120.0", "vnc_chrome_119.0"}, c.matchingTags(tags))
+	c.TagFilter = regexp.MustCompile(`^12`)
+	assert.Equal(t, []string{"120.0"}, c.matchingTags(tags))
+	c.TagFilter = regexp.MustCompile(`.*`)
+	assert.Equal(t, []string{"120.0", "119.0", "vnc_chrome_120.0", "dev", "vnc_chrome_119.0"}, c.matchingTags(tags))
+	c.TagFilter = nil
+	assert.Equal(t, []string{"120.0", "119.0"}, c.matchingTags(tags))
+
+	c = &DockerConfigurator{LastVersions: 1, TagFilter: regexp.MustCompile(`^vnc_`)}
+	assert.Equal(t, []string{"vnc_chrome_120.0"}, c.filterTags(tags, nil))
+}
+
+func TestInvalidTagFilter(t *testing.T) {
+	_, err := NewDockerConfigurator(&LifecycleConfig{RegistryUrl: mockDockerServer.URL, TagFilter: "("})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid tag filter")
+}
@@ -0,0 +1,4 @@
+docker network create selenoid
+docker pull registry.example.com/selenoid/video-recorder:latest-release
+docker run -d --restart always --name selenoid --hostname localhost --network selenoid -p 4444:4444 -v /home/user/.aerokube/selenoid:/etc/selenoid:ro,Z -v /home/user/.aerokube/selenoid/video:/opt/selenoid/video:Z -v /home/user/.aerokube/selenoid/logs:/opt/selenoid/logs:Z -v /var/run/docker.sock:/var/run/docker.sock:Z -e OVERRIDE_VIDEO_OUTPUT_DIR=/home/user/.aerokube/selenoid/video -e DOCKER_API_VERSION=1.29 docker.io/aerokube/selenoid:latest -limit 5 -conf /etc/selenoid/browsers.json -video-output-dir /opt/selenoid/video/ -video-recorder-image registry.example.com/selenoid/video-recorder:latest-release -log-output-dir /opt/selenoid/logs/ -container-network selenoid
+docker run -d --restart always --name selenoid-ui --hostname localhost --network selenoid -p 8080:8080 -e DOCKER_API_VERSION=1.29 registry.example.com/aerokube/selenoid-ui:latest --selenoid-uri=http://selenoid:4444
@@ -0,0 +1,373 @@
+package selenoid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	snapshotsDirName     = "snapshots"
+	snapshotMetadataFile = "snapshot.json"
+	snapshotIDFormat     = "20060102-150405"
+	versionFileSuffix    = ".version"
+	unknownVersion       = "unknown"
+)
+
+var ErrNoSnapshot = errors.New("no snapshot to roll back to")
+
+type BrowserVersion struct {
+	Browser string `json:"browser"`
+	Version string `json:"version"`
+}
+
+// UpgradePlan compares installed Selenoid or Selenoid UI with the one upgrade would install
+type UpgradePlan struct {
+	Service     string           `json:"service"`
+	FromVersion string           `json:"fromVersion"`
+	ToVersion   string           `json:"toVersion"`
+	Added       []BrowserVersion `json:"added,omitempty"`
+	Removed     []BrowserVersion `json:"removed,omitempty"`
+	ui          bool
+}
+
+func (p *UpgradePlan) Empty() bool {
+	return p.FromVersion == p.ToVersion && len(p.Added) == 0 && len(p.Removed) == 0
+}
+
+// Snapshot keeps files and version used before upgrade
+type Snapshot struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	UI      bool      `json:"ui,omitempty"`
+	Version string    `json:"version"`
+	Files   []string  `json:"files,omitempty"`
+}
+
+func NewSnapshotID() string {
+	return time.Now().UTC().Format(snapshotIDFormat)
+}
+
+func (l *Lifecycle) PlanUpgrade() (*UpgradePlan, error) {
+	status := l.Status()
+	plan := &UpgradePlan{Service: status.Service, FromVersion: l.upgradable.InstalledVersion()}
+	toVersion, err := l.upgradable.LatestVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine Selenoid version: %v", err)
+	}
+	plan.ToVersion = toVersion
+	available, err := l.upgradable.AvailableBrowsers()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string][]string)
+	for name, bv := range status.Browsers {
+		current[name] = bv.Versions
+	}
+	plan.Added = browserVersionsDiff(available, current)
+	plan.Removed = browserVersionsDiff(current, available)
+	return plan, nil
+}
+
+func (l *Lifecycle) PlanUIUpgrade() (*UpgradePlan, error) {
+	plan := &UpgradePlan{Service: "Selenoid UI", FromVersion: l.upgradable.InstalledUIVersion(), ui: true}
+	toVersion, err := l.upgradable.LatestUIVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine Selenoid UI version: %v", err)
+	}
+	plan.ToVersion = toVersion
+	return plan, nil
+}
+
+// browserVersionsDiff returns browser versions present in first map only
+func browserVersionsDiff(first map[string][]string, second map[string][]string) []BrowserVersion {
+	var ret []BrowserVersion
+	for name, versions := range first {
+		existing := make(map[string]struct{})
+		for _, version := range second[name] {
+			existing[version] = struct{}{}
+		}
+		for _, version := range versions {
+			if _, ok := existing[version]; !ok {
+				ret = append(ret, BrowserVersion{Browser: name, Version: version})
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Browser != ret[j].Browser {
+			return ret[i].Browser < ret[j].Browser
+		}
+		return ret[i].Version < ret[j].Version
+	})
+	return ret
+}
+
+func (l *Lifecycle) PrintUpgradePlan(plan *UpgradePlan) {
+	from := plan.FromVersion
+	if from == "" {
+		from = "not installed"
+	}
+	if from == plan.ToVersion {
+		l.Pointf("%s version: %s (unchanged)", plan.Service, from)
+	} else {
+		l.Pointf("%s version: %s -> %s", plan.Service, from, color.GreenString(plan.ToVersion))
+	}
+	for _, bv := range plan.Added {
+		l.Pointf("Add browser %s %s", color.GreenString(bv.Browser), bv.Version)
+	}
+	for _, bv := range plan.Removed {
+		l.Pointf("Remove browser %s %s", color.RedString(bv.Browser), bv.Version)
+	}
+}
+
+// Upgrade saves snapshot of current installation and downloads, configures and restarts service
+func (l *Lifecycle) Upgrade(plan *UpgradePlan, snapshotID string) error {
+	snapshot, err := l.createSnapshot(snapshotID, plan)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %v", err)
+	}
+	l.Titlef("Saved %s snapshot %v", plan.Service, color.GreenString(snapshot.ID))
+	l.Force = true
+	if plan.ui {
+		if l.runnable.IsUIRunning() {
+			return l.StartUI()
+		}
+		return l.DownloadUI()
+	}
+	if l.runnable.IsRunning() {
+		return l.Start()
+	}
+	return l.Configure()
+}
+
+func (l *Lifecycle) getSnapshotsDir() string {
+	return filepath.Join(l.Config.ConfigDir, snapshotsDirName)
+}
+
+func (l *Lifecycle) createSnapshot(id string, plan *UpgradePlan) (*Snapshot, error) {
+	snapshot := &Snapshot{ID: id, Created: time.Now(), UI: plan.ui, Version: plan.FromVersion}
+	dir := filepath.Join(l.getSnapshotsDir(), snapshotDirName(id, plan.ui))
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range l.snapshotFiles(plan.ui) {
+		rel, err := filepath.Rel(l.Config.ConfigDir, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		err = copyFile(file, filepath.Join(dir, rel))
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Files = append(snapshot.Files, rel)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, os.WriteFile(filepath.Join(dir, snapshotMetadataFile), data, 0644)
+}
+
+func snapshotDirName(id string, ui bool) string {
+	if ui {
+		return id + "-ui"
+	}
+	return id
+}
+
+// snapshotFiles returns browsers.json and binaries, Docker images stay in local storage
+func (l *Lifecycle) snapshotFiles(ui bool) []string {
+	var ret []string
+	status := l.Status()
+	if ui {
+		status = l.UIStatus()
+	}
+	if status.Binary != "" {
+		ret = append(ret, status.Binary)
+		if fileExists(status.Binary + versionFileSuffix) {
+			ret = append(ret, status.Binary+versionFileSuffix)
+		}
+	}
+	if ui || status.ConfigPath == "" {
+		return ret
+	}
+	ret = append(ret, status.ConfigPath)
+	data, err := os.ReadFile(status.ConfigPath)
+	if err != nil {
+		return ret
+	}
+	var cfg SelenoidConfig
+	if json.Unmarshal(data, &cfg) != nil {
+		return ret
+	}
+	for _, versions := range cfg {
+		for _, browser := range versions.Versions {
+			if command, ok := browser.Image.([]interface{}); ok && len(command) > 0 {
+				if driver, ok := command[0].(string); ok && fileExists(driver) {
+					ret = append(ret, driver)
+				}
+			}
+		}
+	}
+	return uniqueStrings(ret)
+}
+
+// Snapshots returns saved snapshots starting from the newest one
+func (l *Lifecycle) Snapshots(ui bool) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(l.getSnapshotsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ret []*Snapshot
+	for _, entry := range entries {
+		var snapshot Snapshot
+		data, err := os.ReadFile(filepath.Join(l.getSnapshotsDir(), entry.Name(), snapshotMetadataFile))
+		if err != nil || json.Unmarshal(data, &snapshot) != nil {
+			continue
+		}
+		if snapshot.UI == ui {
+			ret = append(ret, &snapshot)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Created.After(ret[j].Created)
+	})
+	return ret, nil
+}
+
+// Rollback restores snapshot with specified ID or the last one and restarts service if it is running
+func (l *Lifecycle) Rollback(ui bool, id string) (*Snapshot, error) {
+	snapshots, err := l.Snapshots(ui)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %v", err)
+	}
+	var snapshot *Snapshot
+	for _, s := range snapshots {
+		if id == "" || s.ID == id {
+			snapshot = s
+			break
+		}
+	}
+	if snapshot == nil {
+		return nil, ErrNoSnapshot
+	}
+	service := "Selenoid"
+	if ui {
+		service = "Selenoid UI"
+	}
+	l.Titlef("Rolling back %s to snapshot %v (version %s)...", service, color.GreenString(snapshot.ID), snapshot.Version)
+
+	cfg := *l.Config
+	if snapshot.Version != "" && snapshot.Version != unknownVersion {
+		cfg.Version = snapshot.Version
+	}
+	previous, err := NewLifecycle(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer previous.Close()
+	running := previous.runnable.IsRunning()
+	if ui {
+		running = previous.runnable.IsUIRunning()
+	}
+	if running {
+		err = previous.stopService(ui)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dir := filepath.Join(l.getSnapshotsDir(), snapshotDirName(snapshot.ID, ui))
+	for _, file := range snapshot.Files {
+		l.Pointf("Restoring %v", color.BlueString(file))
+		err = copyFile(filepath.Join(dir, file), filepath.Join(l.Config.ConfigDir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %v", file, err)
+		}
+	}
+	if running {
+		err = previous.startService(ui)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, os.RemoveAll(dir)
+}
+
+// RollbackLastUpgrade restores snapshots saved by the last upgrade. Upgrade could change only Selenoid or only Selenoid UI,
+// so snapshot ID is taken from the newest snapshot of both and missing snapshot of the other service is skipped
+func RollbackLastUpgrade(l *Lifecycle, ui *Lifecycle) (string, error) {
+	id, err := lastSnapshotID(l, ui)
+	if err != nil {
+		return "", err
+	}
+	_, err = l.Rollback(false, id)
+	if err != nil && !errors.Is(err, ErrNoSnapshot) {
+		return "", fmt.Errorf("failed to roll back Selenoid: %v", err)
+	}
+	_, err = ui.Rollback(true, id)
+	if err != nil && !errors.Is(err, ErrNoSnapshot) {
+		return "", fmt.Errorf("failed to roll back Selenoid UI: %v", err)
+	}
+	return id, nil
+}
+
+func lastSnapshotID(l *Lifecycle, ui *Lifecycle) (string, error) {
+	snapshots, err := l.Snapshots(false)
+	if err != nil {
+		return "", fmt.Errorf("failed to list snapshots: %v", err)
+	}
+	uiSnapshots, err := ui.Snapshots(true)
+	if err != nil {
+		return "", fmt.Errorf("failed to list snapshots: %v", err)
+	}
+	var last *Snapshot
+	for _, s := range append(snapshots, uiSnapshots...) {
+		if last == nil || s.Created.After(last.Created) {
+			last = s
+		}
+	}
+	if last == nil {
+		return "", ErrNoSnapshot
+	}
+	return last.ID, nil
+}
+
+func (l *Lifecycle) stopService(ui bool) error {
+	if ui {
+		return l.StopUI()
+	}
+	return l.Stop()
+}
+
+func (l *Lifecycle) startService(ui bool) error {
+	l.Force = false
+	if ui {
+		return l.StartUI()
+	}
+	return l.Start()
+}
+
+func copyFile(from string, to string) error {
+	f, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return outputFile(to, fi.Mode(), f)
+}
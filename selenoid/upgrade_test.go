@@ -0,0 +1,130 @@
+package selenoid
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func driversUpgradeConfig(dir string, version string, browsers string) *LifecycleConfig {
+	return &LifecycleConfig{
+		UseDrivers:     true,
+		Quiet:          true,
+		Force:          true,
+		Download:       true,
+		ConfigDir:      dir,
+		CacheDir:       filepath.Join(dir, "cache"),
+		Browsers:       browsers,
+		DriversInfoUrl: mockServerUrl(mockDriverServer, "/browsers.json"),
+		GithubBaseUrl:  mockDriverServer.URL + "/",
+		OS:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		Version:        version,
+	}
+}
+
+func TestUpgradeAndRollback(t *testing.T) {
+	withTmpDir(t, "test-upgrade", func(t *testing.T, dir string) {
+		previous, err := NewLifecycle(driversUpgradeConfig(dir, previousReleaseTag, "first"))
+		assert.NoError(t, err)
+		assert.NoError(t, previous.Configure())
+
+		lc, err := NewLifecycle(driversUpgradeConfig(dir, Latest, "first;second"))
+		assert.NoError(t, err)
+		plan, err := lc.PlanUpgrade()
+		assert.NoError(t, err)
+		assert.Equal(t, previousReleaseTag, plan.FromVersion)
+		assert.Equal(t, latestReleaseTag, plan.ToVersion)
+		assert.Equal(t, []BrowserVersion{{Browser: "second", Version: Latest}}, plan.Added)
+		assert.Empty(t, plan.Removed)
+		assert.False(t, plan.Empty())
+
+		assert.NoError(t, lc.Upgrade(plan, NewSnapshotID()))
+		binaryPath := filepath.Join(dir, getSelenoidReleaseFileName())
+		checkContentsEqual(t, binaryPath, latestReleaseTag)
+		assert.Len(t, readBrowsersStatus(getSelenoidConfigPath(dir)), 2)
+
+		plan, err = lc.PlanUpgrade()
+		assert.NoError(t, err)
+		assert.True(t, plan.Empty())
+
+		snapshots, err := lc.Snapshots(false)
+		assert.NoError(t, err)
+		assert.Len(t, snapshots, 1)
+		snapshot, err := lc.Rollback(false, "")
+		assert.NoError(t, err)
+		assert.Equal(t, previousReleaseTag, snapshot.Version)
+		checkContentsEqual(t, binaryPath, previousReleaseTag)
+		assert.Len(t, readBrowsersStatus(getSelenoidConfigPath(dir)), 1)
+
+		_, err = lc.Rollback(false, "")
+		assert.Equal(t, ErrNoSnapshot, err)
+	})
+}
+
+func TestRollbackUIOnlyUpgrade(t *testing.T) {
+	withTmpDir(t, "test-rollback", func(t *testing.T, dir string) {
+		lc, err := NewLifecycle(driversUpgradeConfig(dir, Latest, "first"))
+		assert.NoError(t, err)
+		_, err = RollbackLastUpgrade(lc, lc)
+		assert.Equal(t, ErrNoSnapshot, err)
+
+		_, err = lc.createSnapshot("older", &UpgradePlan{FromVersion: previousReleaseTag})
+		assert.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		_, err = lc.createSnapshot("last", &UpgradePlan{FromVersion: previousReleaseTag, ui: true})
+		assert.NoError(t, err)
+
+		id, err := RollbackLastUpgrade(lc, lc)
+		assert.NoError(t, err)
+		assert.Equal(t, "last", id)
+		snapshots, err := lc.Snapshots(false)
+		assert.NoError(t, err)
+		assert.Len(t, snapshots, 1)
+		assert.Equal(t, "older", snapshots[0].ID)
+		uiSnapshots, err := lc.Snapshots(true)
+		assert.NoError(t, err)
+		assert.Empty(t, uiSnapshots)
+	})
+}
+
+func TestPlanDockerUpgrade(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://"+hostPort(mockDockerServer.URL))
+	withTmpDir(t, "test-upgrade", func(t *testing.T, dir string) {
+		data := `{
+			"firefox": {"default": "45.0", "versions": {"45.0": {"image": "selenoid/firefox:45.0"}, "46.0": {"image": "selenoid/firefox:46.0"}}},
+			"opera": {"default": "44.0", "versions": {"44.0": {"image": "selenoid/opera:44.0"}}}
+		}`
+		assert.NoError(t, os.WriteFile(getSelenoidConfigPath(dir), []byte(data), 0644))
+		c, err := NewDockerConfigurator(&LifecycleConfig{
+			ConfigDir:    dir,
+			RegistryUrl:  mockDockerServer.URL,
+			Quiet:        true,
+			LastVersions: 1,
+			Browsers:     "firefox;android",
+			Version:      "1.4.1",
+		})
+		assert.NoError(t, err)
+		defer c.Close()
+		lc := &Lifecycle{Config: &LifecycleConfig{ConfigDir: dir}, statusAware: c, upgradable: c}
+
+		plan, err := lc.PlanUpgrade()
+		assert.NoError(t, err)
+		assert.Equal(t, Latest, plan.FromVersion)
+		assert.Equal(t, "1.4.1", plan.ToVersion)
+		assert.Equal(t, []BrowserVersion{{Browser: "android", Version: "10.0"}}, plan.Added)
+		assert.Equal(t, []BrowserVersion{{Browser: "firefox", Version: "45.0"}, {Browser: "opera", Version: "44.0"}}, plan.Removed)
+	})
+}
+
+func TestBrowserVersionsDiff(t *testing.T) {
+	diff := browserVersionsDiff(
+		map[string][]string{"chrome": {"120.0", "20.0"}, "firefox": {"121.0"}},
+		map[string][]string{"chrome": {"120.0"}},
+	)
+	assert.Equal(t, []BrowserVersion{{Browser: "chrome", Version: "20.0"}, {Browser: "firefox", Version: "121.0"}}, diff)
+}